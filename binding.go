@@ -0,0 +1,377 @@
+package gorn
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Error of Field Failed to Bind or Validate
+type FieldError struct {
+	// Name of Field (Key of Value, e.g. "page" or "filter.page")
+	Field string `json:"field"`
+
+	// Source of Value (query, form, header, path)
+	Source string `json:"source,omitempty"`
+
+	// Value Failed to Bind
+	Value string `json:"value,omitempty"`
+
+	// Failed Validation Rule & Parameter (e.g. "max", "20")
+	Rule  string `json:"rule,omitempty"`
+	Param string `json:"param,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// List of Field Errors
+// Sent as Details of Bad Request (400) or Unprocessable Entity (422)
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// source of values bound to fields with tag
+type bindSource struct {
+	tag    string
+	lookup func(key string) ([]string, bool)
+}
+
+// Binding Query Params to Fields with `query` Tag
+// If Value Can't be Parsed, Send Bad Request (400) & Return Error with FieldErrors
+func (c *Context) BindQuery(obj interface{}) error {
+	return c.bindSources(obj, c.querySource())
+}
+
+// Binding Form Values (URL-Encoded or Multipart) to Fields with `form` Tag
+// If Value Can't be Parsed, Send Bad Request (400) & Return Error with FieldErrors
+func (c *Context) BindForm(obj interface{}) error {
+	source, err := c.formSource()
+	if err != nil {
+		return c.requestError(err)
+	}
+	return c.bindSources(obj, source)
+}
+
+// Binding Request Headers to Fields with `header` Tag
+// If Value Can't be Parsed, Send Bad Request (400) & Return Error with FieldErrors
+func (c *Context) BindHeader(obj interface{}) error {
+	return c.bindSources(obj, c.headerSource())
+}
+
+// Binding Path Parameters to Fields with `path` Tag
+// If Value Can't be Parsed, Send Bad Request (400) & Return Error with FieldErrors
+func (c *Context) BindPath(obj interface{}) error {
+	return c.bindSources(obj, c.pathSource())
+}
+
+// Binding Request to Fields & Validate Them (See Validate)
+// Body is Decoded by Content-Type (Json, XML or Form), Then Path, Query & Header Values are Bound.
+// If Content-Type is Not Supported, Send Unsupported Media Type (415) & Return Error
+func (c *Context) Bind(obj interface{}) error {
+	if err := checkBindTarget(obj); err != nil {
+		return c.requestError(err)
+	}
+	sources := make([]*bindSource, 0, 4)
+	switch mediaType := c.mediaType(); {
+	case mediaType == "" && c.request.ContentLength == 0:
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if err := c.decodeJson(obj); err != nil {
+			return c.requestError(err)
+		}
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		if err := c.decodeXML(obj); err != nil {
+			return c.requestError(err)
+		}
+	case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+		source, err := c.formSource()
+		if err != nil {
+			return c.requestError(err)
+		}
+		sources = append(sources, source)
+	default:
+		return c.requestError(NewHTTPError(http.StatusUnsupportedMediaType, "unsupported media type"))
+	}
+	sources = append(sources, c.pathSource(), c.querySource(), c.headerSource())
+	if err := c.bindSources(obj, sources...); err != nil {
+		return err
+	}
+	return c.Validate(obj)
+}
+
+// get media type of request body
+func (c *Context) mediaType() string {
+	contentType := c.GetHeader("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
+
+// source of query params
+func (c *Context) querySource() *bindSource {
+	values := c.queryValues()
+	return &bindSource{"query", valuesLookup(values)}
+}
+
+// source of form values
+// Multipart form is parsed with upload limits of router
+func (c *Context) formSource() (*bindSource, *HTTPError) {
+	if c.mediaType() == "multipart/form-data" {
+		if _, err := c.parseMultipartForm(); err != nil {
+			return nil, err
+		}
+	} else if err := c.request.ParseForm(); err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, "bad request").WithErr(err)
+	}
+	return &bindSource{"form", valuesLookup(c.request.PostForm)}, nil
+}
+
+// source of request headers
+func (c *Context) headerSource() *bindSource {
+	return &bindSource{"header", func(key string) ([]string, bool) {
+		values := c.request.Header.Values(key)
+		return values, len(values) > 0
+	}}
+}
+
+// source of path params
+func (c *Context) pathSource() *bindSource {
+	return &bindSource{"path", func(key string) ([]string, bool) {
+		param, ok := c.pathParam(key)
+		return []string{param.value}, ok
+	}}
+}
+
+// lookup function of url values
+func valuesLookup(values url.Values) func(key string) ([]string, bool) {
+	return func(key string) ([]string, bool) {
+		v := values[key]
+		return v, len(v) > 0
+	}
+}
+
+// check binding target is pointer to struct
+func checkBindTarget(obj interface{}) *HTTPError {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		err := fmt.Errorf("gorn: binding target must be non-nil pointer to struct, got %T", obj)
+		return NewHTTPError(http.StatusInternalServerError, "internal server error").WithErr(err)
+	}
+	return nil
+}
+
+// bind values of sources to object
+func (c *Context) bindSources(obj interface{}, sources ...*bindSource) error {
+	if err := checkBindTarget(obj); err != nil {
+		return c.requestError(err)
+	}
+	v := reflect.ValueOf(obj).Elem()
+	var errs FieldErrors
+	for _, source := range sources {
+		_, sourceErrs := bindStruct(v, source, "")
+		errs = append(errs, sourceErrs...)
+	}
+	if len(errs) > 0 {
+		return c.requestError(NewHTTPError(http.StatusBadRequest, "bad request").WithDetails(errs).WithErr(errs))
+	}
+	return nil
+}
+
+// bind values of source to fields of struct
+// Nested struct with tag name is bound with prefix (e.g. "filter.page"), without tag name is flattened
+// Return whether any field is bound
+func bindStruct(v reflect.Value, source *bindSource, prefix string) (bool, FieldErrors) {
+	var errs FieldErrors
+	bound := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup(source.tag)
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if isNestedStruct(field.Type) {
+			nestedPrefix := prefix
+			if name != "" {
+				nestedPrefix = prefix + name + "."
+			}
+			nestedBound, nestedErrs := bindNested(fv, source, nestedPrefix)
+			bound = bound || nestedBound
+			errs = append(errs, nestedErrs...)
+			continue
+		}
+		if !hasTag || name == "" || !fv.CanSet() {
+			continue
+		}
+		key := prefix + name
+		values, ok := source.lookup(key)
+		if !ok {
+			continue
+		}
+		bound = true
+		if err := setField(fv, values); err != nil {
+			errs = append(errs, &FieldError{
+				Field:   key,
+				Source:  source.tag,
+				Value:   strings.Join(values, ","),
+				Message: err.Error(),
+			})
+		}
+	}
+	return bound, errs
+}
+
+// bind values of source to nested struct
+// Nil pointer is allocated only if any field is bound
+func bindNested(v reflect.Value, source *bindSource, prefix string) (bool, FieldErrors) {
+	if v.Kind() != reflect.Ptr {
+		return bindStruct(v, source, prefix)
+	}
+	if !v.IsNil() {
+		return bindStruct(v.Elem(), source, prefix)
+	}
+	if !v.CanSet() {
+		return false, nil
+	}
+	nv := reflect.New(v.Type().Elem())
+	bound, errs := bindStruct(nv.Elem(), source, prefix)
+	if bound {
+		v.Set(nv)
+	}
+	return bound, errs
+}
+
+// check type is struct bound field by field
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// set values to field (Slice gets every value, others get first value)
+func setField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return setValue(v, values[0])
+}
+
+// parse value & set it to field
+// Empty value keeps field unchanged except string
+func setValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Ptr {
+		if value == "" {
+			return nil
+		}
+		nv := reflect.New(v.Type().Elem())
+		if err := setValue(nv.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(nv)
+		return nil
+	}
+	if value == "" && v.Kind() != reflect.String {
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse("2006-01-02", value)
+		}
+		if err != nil {
+			return errors.New("must be time (RFC 3339) or date (2006-01-02)")
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("must be duration (e.g. 1h30m)")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return numberError(err, "integer")
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return numberError(err, "non-negative integer")
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return numberError(err, "number")
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// error of number parsing
+func numberError(err error, kind string) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return errors.New("out of range")
+	}
+	return fmt.Errorf("must be %s", kind)
+}
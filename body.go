@@ -0,0 +1,244 @@
+package gorn
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Set Max Size of Request Body of Route
+// Size Takes Precedence Over Router Option (Negative Size Means No Limit)
+func (rt *Route) MaxBodySize(size int64) *Route {
+	if rt == nil || size == 0 {
+		return rt
+	}
+	rt.maxBodySize = size
+	rt.node.setBodyLimit(rt.method, size)
+	return rt
+}
+
+// limit request body to max body size of route or router
+// If body is known to be too large, send request entity too large (413) & return false
+func (r *Router) limitBody(c *Context, n *node) bool {
+	limit := n.bodyLimit(c.request.Method)
+	if limit == 0 {
+		limit = r.options.MaxBodySize
+	}
+	if limit <= 0 {
+		return true
+	}
+	if c.request.ContentLength > limit {
+		c.SendError(http.StatusRequestEntityTooLarge, "request entity too large")
+		return false
+	}
+	// writer of net/http is passed to close connection after limit is exceeded
+	c.request.Body = http.MaxBytesReader(c.responseWriter.ResponseWriter, c.request.Body, limit)
+	return true
+}
+
+// http error of body exceeding max body size (nil if error is not caused by limit)
+func tooLargeError(err error) *HTTPError {
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		return nil
+	}
+	return NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit)).WithErr(err)
+}
+
+// Binding Body to Json Object Strictly & Validate It (See Validate)
+// Unknown Fields, Duplicate Keys & Trailing Data After Json Value are Rejected.
+// If Body Can't Decode to Json Object, Send Bad Request (400) & Return Error
+func (c *Context) BindJsonBodyStrict(obj interface{}) error {
+	if err := c.decodeJsonStrict(obj); err != nil {
+		return c.requestError(err)
+	}
+	return c.Validate(obj)
+}
+
+// check json body is decoded strictly by router option
+func (c *Context) strictJson() bool {
+	return c.router != nil && c.router.options != nil && c.router.options.StrictJson
+}
+
+// decode json body to object strictly
+func (c *Context) decodeJsonStrict(obj interface{}) *HTTPError {
+	data, err := io.ReadAll(c.request.Body)
+	if err != nil {
+		if httpErr := tooLargeError(err); httpErr != nil {
+			return httpErr
+		}
+		return NewHTTPError(http.StatusBadRequest, "bad request").WithErr(err)
+	}
+	if err := checkJsonKeys(data); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return jsonError(err, decoder.InputOffset())
+	}
+	return nil
+}
+
+// frame of object or array in json value
+type jsonFrame struct {
+	object    bool
+	keys      map[string]bool
+	key       string
+	index     int
+	expectKey bool
+}
+
+// check json value has no duplicate keys & no trailing data
+func checkJsonKeys(data []byte) *HTTPError {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var stack []*jsonFrame
+
+	// mark value of top frame is finished
+	valueDone := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.object {
+			top.expectKey = true
+		} else {
+			top.index++
+		}
+	}
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return jsonError(err, decoder.InputOffset())
+		}
+		if len(stack) > 0 && stack[len(stack)-1].object && stack[len(stack)-1].expectKey {
+			top := stack[len(stack)-1]
+			if token == json.Delim('}') {
+				stack = stack[:len(stack)-1]
+				valueDone()
+			} else {
+				key := token.(string)
+				if top.keys[key] {
+					field := jsonPath(stack, key)
+					errs := FieldErrors{{Field: field, Source: "body", Message: fmt.Sprintf("duplicate key at offset %d", offset)}}
+					return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("duplicate key '%s' at offset %d", field, offset)).WithDetails(errs).WithErr(errs)
+				}
+				top.keys[key] = true
+				top.key = key
+				top.expectKey = false
+			}
+		} else {
+			switch token {
+			case json.Delim('{'):
+				stack = append(stack, &jsonFrame{object: true, keys: make(map[string]bool), expectKey: true})
+			case json.Delim('['):
+				stack = append(stack, &jsonFrame{})
+			case json.Delim(']'):
+				stack = stack[:len(stack)-1]
+				valueDone()
+			default:
+				valueDone()
+			}
+		}
+		if len(stack) == 0 {
+			break
+		}
+	}
+
+	offset := decoder.InputOffset()
+	if _, err := decoder.Token(); err != io.EOF {
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("trailing data after json value at offset %d", offset))
+	}
+	return nil
+}
+
+// get path of key in json value (e.g. "items[0].name")
+func jsonPath(stack []*jsonFrame, key string) string {
+	var b strings.Builder
+	for _, frame := range stack[:len(stack)-1] {
+		if frame.object {
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(frame.key)
+		} else {
+			b.WriteString("[" + strconv.Itoa(frame.index) + "]")
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteByte('.')
+	}
+	b.WriteString(key)
+	return b.String()
+}
+
+// get kind of json value decoded to go type (object, array, string, number, boolean)
+func jsonKind(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		// []byte is base64 string
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "value"
+}
+
+// http error of json decoding with offending field or offset
+func jsonError(err error, offset int64) *HTTPError {
+	if httpErr := tooLargeError(err); httpErr != nil {
+		return httpErr
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return NewHTTPError(http.StatusBadRequest, "empty json body").WithErr(err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewHTTPError(http.StatusBadRequest, "unexpected end of json body").WithErr(err)
+	case errors.As(err, &syntaxErr):
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid json at offset %d: %s", syntaxErr.Offset, strings.TrimPrefix(syntaxErr.Error(), "json: "))).WithErr(err)
+	case errors.As(err, &typeErr):
+		// go type is kept in Err only (type of anonymous struct is full struct literal)
+		kind := jsonKind(typeErr.Type)
+		errs := FieldErrors{{Field: typeErr.Field, Source: "body", Value: typeErr.Value, Message: fmt.Sprintf("must be %s (offset %d)", kind, typeErr.Offset)}}
+		if typeErr.Field == "" {
+			return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("json body must be %s, got %s", kind, typeErr.Value)).WithDetails(errs).WithErr(err)
+		}
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("field '%s' must be %s at offset %d", typeErr.Field, kind, typeErr.Offset)).WithDetails(errs).WithErr(err)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		errs := FieldErrors{{Field: field, Source: "body", Message: fmt.Sprintf("unknown field (offset %d)", offset)}}
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown field '%s' at offset %d", field, offset)).WithDetails(errs).WithErr(err)
+	}
+	return NewHTTPError(http.StatusBadRequest, "bad request").WithErr(err)
+}
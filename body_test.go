@@ -0,0 +1,182 @@
+package gorn
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJsonTypeError(t *testing.T) {
+	type item struct {
+		A     int      `json:"a" validate:"min=2"`
+		Tags  []string `json:"tags"`
+		Inner struct {
+			B bool `json:"b"`
+		} `json:"inner"`
+	}
+	tests := []struct {
+		name    string
+		body    string
+		message string
+		field   string
+	}{
+		{"array to struct", `[1,2]`, "json body must be object, got array", ""},
+		{"string to number", `{"a":"1"}`, "field 'a' must be number at offset", "a"},
+		{"object to array", `{"tags":{}}`, "field 'tags' must be array at offset", "tags"},
+		{"number to boolean", `{"inner":{"b":1}}`, "field 'inner.b' must be boolean at offset", "inner.b"},
+	}
+	for _, strict := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := NewRouter()
+				r.SetOptions(&RouterOptions{StrictJson: strict})
+				r.SetErrorRenderer(JsonErrorRenderer)
+				var bindErr error
+				r.Post("/", func(c *Context) {
+					bindErr = c.BindJsonBody(&item{})
+				})
+				w := serveTestRequest(r, newJsonRequest(http.MethodPost, "/", tt.body))
+				if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.message) {
+					t.Errorf("strict %v: got %d %s, want 400 containing %q", strict, w.Code, w.Body.String(), tt.message)
+				}
+				if strings.Contains(w.Body.String(), "struct") || strings.Contains(w.Body.String(), "validate") {
+					t.Errorf("strict %v: go type is sent to client: %s", strict, w.Body.String())
+				}
+				httpErr, ok := bindErr.(*HTTPError)
+				if !ok || httpErr.Details.(FieldErrors)[0].Field != tt.field || httpErr.Err == nil {
+					t.Errorf("strict %v: error = %#v, want field error of %q with cause", strict, bindErr, tt.field)
+				}
+			})
+		}
+	}
+}
+
+func TestMaxBodySizeClosesConnection(t *testing.T) {
+	r := NewRouter()
+	r.SetOptions(&RouterOptions{MaxBodySize: 16})
+	r.Post("/", func(c *Context) {
+		c.BindJsonBody(&bindTestBody{})
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	// body of unknown length is limited while reading
+	body := io.MultiReader(strings.NewReader(`{"name":"`), strings.NewReader(strings.Repeat("a", 1024)+`"}`))
+	req, _ := http.NewRequest(http.MethodPost, server.URL, body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge || !resp.Close {
+		t.Errorf("got %d with close %v, want 413 with connection closed", resp.StatusCode, resp.Close)
+	}
+}
+
+func TestStrictJson(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Inner struct {
+			B bool `json:"b"`
+		} `json:"inner"`
+	}
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		message string
+	}{
+		{"valid", `{"name":"a","inner":{"b":true}}`, http.StatusOK, "ok"},
+		{"duplicate key", `{"name":"a","name":"b"}`, http.StatusBadRequest, "duplicate key 'name' at offset"},
+		{"nested duplicate key", `{"inner":{"b":true,"b":false}}`, http.StatusBadRequest, "duplicate key 'inner.b' at offset"},
+		{"trailing data", `{"name":"a"} {}`, http.StatusBadRequest, "trailing data after json value at offset"},
+		{"trailing garbage", `{"name":"a"}x`, http.StatusBadRequest, "trailing data after json value at offset"},
+		{"trailing whitespace", "{\"name\":\"a\"}\n", http.StatusOK, "ok"},
+		{"unknown field", `{"name":"a","age":1}`, http.StatusBadRequest, "unknown field 'age' at offset"},
+		{"nested unknown field", `{"inner":{"c":1}}`, http.StatusBadRequest, "unknown field 'c' at offset"},
+	}
+	binds := []struct {
+		name    string
+		options *RouterOptions
+		bind    func(c *Context, obj interface{}) error
+	}{
+		{"BindJsonBodyStrict", nil, (*Context).BindJsonBodyStrict},
+		{"StrictJson option", &RouterOptions{StrictJson: true}, (*Context).BindJsonBody},
+		{"StrictJson option of Bind", &RouterOptions{StrictJson: true}, (*Context).Bind},
+	}
+	for _, b := range binds {
+		for _, tt := range tests {
+			t.Run(b.name+"/"+tt.name, func(t *testing.T) {
+				r := NewRouter()
+				r.SetOptions(b.options)
+				r.Post("/", func(c *Context) {
+					if b.bind(c, &item{}) == nil {
+						c.SendPlainText(http.StatusOK, "ok")
+					}
+				})
+				w := serveTestRequest(r, newJsonRequest(http.MethodPost, "/", tt.body))
+				if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.message) {
+					t.Errorf("got %d %q, want %d containing %q", w.Code, w.Body.String(), tt.status, tt.message)
+				}
+			})
+		}
+	}
+}
+
+func TestNonStrictJson(t *testing.T) {
+	r := NewRouter()
+	r.Post("/", func(c *Context) {
+		if c.BindJsonBody(&bindTestBody{}) == nil {
+			c.SendPlainText(http.StatusOK, "ok")
+		}
+	})
+	for _, body := range []string{`{"name":"a","name":"b"}`, `{"name":"a","age":1}`} {
+		if w := serveTestRequest(r, newJsonRequest(http.MethodPost, "/", body)); w.Code != http.StatusOK {
+			t.Errorf("non-strict body %s: got %d %q, want 200", body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	small := `{"name":"a"}`
+	large := `{"name":"` + strings.Repeat("a", 32) + `"}`
+	tests := []struct {
+		name    string
+		route   int64
+		body    string
+		chunked bool
+		status  int
+		message string
+	}{
+		{"under router limit", 0, small, false, http.StatusOK, "ok"},
+		{"over router limit", 0, large, false, http.StatusRequestEntityTooLarge, "request entity too large"},
+		{"over router limit of unknown length", 0, large, true, http.StatusRequestEntityTooLarge, "request body exceeds 16 bytes"},
+		{"route limit takes precedence", 64, large, false, http.StatusOK, "ok"},
+		{"over route limit", 8, small, false, http.StatusRequestEntityTooLarge, "request entity too large"},
+		{"over route limit of unknown length", 8, small, true, http.StatusRequestEntityTooLarge, "request body exceeds 8 bytes"},
+		{"route without limit", -1, large, true, http.StatusOK, "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.SetOptions(&RouterOptions{MaxBodySize: 16})
+			r.Post("/", func(c *Context) {
+				if c.BindJsonBody(&bindTestBody{}) == nil {
+					c.SendPlainText(http.StatusOK, "ok")
+				}
+			}).MaxBodySize(tt.route)
+			req := newJsonRequest(http.MethodPost, "/", tt.body)
+			if tt.chunked {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(strings.NewReader(tt.body))
+			}
+			w := serveTestRequest(r, req)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("got %d %q, want %d containing %q", w.Code, w.Body.String(), tt.status, tt.message)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package gorn
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -19,14 +18,64 @@ const (
 )
 
 type Context struct {
-	// HTTP Response Writer
-	responseWriter http.ResponseWriter
+	// HTTP Response Writer (Points to writer)
+	responseWriter *statusWriter
+	writer         statusWriter
 
 	// HTTP Request Handler
 	request *http.Request
 
 	// Custom Context Value
 	ctx context.Context
+
+	// Router Serving Request
+	router *Router
+
+	// Running Error Returning Handler (WithError)
+	returnErrors bool
+
+	// Error of Parsing Multipart Form (Returned Again by Later Calls)
+	multipartErr *HTTPError
+
+	// Path & Host Parameters
+	pathParams []pathParam
+	hostParams map[string]string
+
+	// Handler Chain & Index of Running Handler
+	handlers []func(c *Context)
+	index    int
+	aborted  bool
+}
+
+//================================================================================
+// HANDLER CHAIN
+//================================================================================
+
+// Run Remaining Handlers in Chain
+// Middleware Can Run Code After Downstream Handlers by Calling Next
+// Chain Stops When Context is Finished or Aborted
+func (c *Context) Next() {
+	// downstream handlers send their own errors unless they are wrapped by WithError
+	returnErrors := c.returnErrors
+	c.returnErrors = false
+	c.index++
+	for c.index < len(c.handlers) && !c.IsContextFinish() {
+		c.handlers[c.index](c)
+		c.index++
+	}
+	c.returnErrors = returnErrors
+}
+
+// Abort Remaining Handlers in Chain
+// Handlers Already Running Continue After Next Returns
+func (c *Context) Abort() {
+	c.aborted = true
+	c.index = len(c.handlers)
+}
+
+// Check Context is Aborted
+func (c *Context) IsAborted() bool {
+	return c.aborted
 }
 
 //================================================================================
@@ -38,6 +87,17 @@ func (c *Context) GetResponseWriter() http.ResponseWriter {
 	return c.responseWriter
 }
 
+// Check Response Status is Written
+func (c *Context) IsWritten() bool {
+	return c.responseWriter.status != 0
+}
+
+// Get Written Response Status
+// If Status is Not Written, Return 0
+func (c *Context) GetStatus() int {
+	return c.responseWriter.status
+}
+
 // Flagging Context is Finished
 func (c *Context) SetContextFinish() {
 	c.ctx = context.WithValue(c.ctx, ContextFinish, true)
@@ -51,28 +111,51 @@ func (c *Context) IsContextFinish() bool {
 	return c.ctx.Value(ContextFinish).(bool)
 }
 
+// Send Error Response
+// Body is Rendered by Error Renderer of Router (Default Plain Text)
+func (c *Context) SendError(status int, message string) {
+	c.SendHTTPError(&HTTPError{Status: status, Message: message})
+}
+
+// Send HTTP Error Response with Code & Details
+// Body is Rendered by Error Renderer of Router (Default Plain Text)
+func (c *Context) SendHTTPError(err *HTTPError) {
+	c.SetContextFinish()
+	if c.router != nil && c.router.errorRenderer != nil {
+		c.router.errorRenderer(c, err)
+		return
+	}
+	PlainErrorRenderer(c, err)
+}
+
 // Send Internal Server Error (500)
 func (c *Context) SendInternalServerError() {
-	c.SetContextFinish()
-	http.Error(c.responseWriter, "internal server error", http.StatusInternalServerError)
+	c.SendError(http.StatusInternalServerError, "internal server error")
 }
 
 // Send Bad Request (400)
 func (c *Context) SendBadRequest() {
-	c.SetContextFinish()
-	http.Error(c.responseWriter, "bad request", http.StatusBadRequest)
+	c.SendError(http.StatusBadRequest, "bad request")
 }
 
 // Send Not Authorized (401)
 func (c *Context) SendNotAuthorized() {
-	c.SetContextFinish()
-	http.Error(c.responseWriter, "not authorized", http.StatusUnauthorized)
+	c.SendError(http.StatusUnauthorized, "not authorized")
+}
+
+// Send Forbidden (403)
+func (c *Context) SendForbidden() {
+	c.SendError(http.StatusForbidden, "forbidden")
+}
+
+// Send Not Found (404)
+func (c *Context) SendNotFound() {
+	c.SendError(http.StatusNotFound, "not found")
 }
 
 // Send Method Not Allowed (405)
 func (c *Context) SendMethodNotAllowed() {
-	c.SetContextFinish()
-	http.Error(c.responseWriter, "method not allowed", http.StatusMethodNotAllowed)
+	c.SendError(http.StatusMethodNotAllowed, "method not allowed")
 }
 
 // Send Success (200)
@@ -122,13 +205,33 @@ func (c *Context) GetRequest() *http.Request {
 	return c.request
 }
 
-// Binding Body to Json Object
+// send status of request error (e.g. bad request (400)) & return error
+// In error returning handler (WithError), error is only returned to be rendered by error handler
+func (c *Context) requestError(err *HTTPError) error {
+	if !c.returnErrors {
+		c.SendHTTPError(err)
+	}
+	return err
+}
+
+// Binding Body to Json Object & Validate It (See Validate)
 // If Body Can't Decode to Json Object, Send Bad Request (400) & Return Error
+// If Body Exceeds Max Body Size, Send Request Entity Too Large (413) & Return Error
 func (c *Context) BindJsonBody(obj interface{}) error {
+	if err := c.decodeJson(obj); err != nil {
+		return c.requestError(err)
+	}
+	return c.Validate(obj)
+}
+
+// decode json body to object (Strictly if router option is set)
+func (c *Context) decodeJson(obj interface{}) *HTTPError {
+	if c.strictJson() {
+		return c.decodeJsonStrict(obj)
+	}
 	decoder := json.NewDecoder(c.request.Body)
 	if err := decoder.Decode(obj); err != nil {
-		c.SendBadRequest()
-		return err
+		return jsonError(err, decoder.InputOffset())
 	}
 	return nil
 }
@@ -176,6 +279,66 @@ func (c *Context) GetParamBool(key string, defaultValue bool) bool {
 	return b
 }
 
+// Get Path Parameter Value From name
+// If Name Not Found, Return Empty String
+func (c *Context) PathParam(name string) string {
+	param, _ := c.pathParam(name)
+	return param.value
+}
+
+// Get Path Parameter integer Value From name
+// If Name Not Found, Return default Value
+func (c *Context) PathParamInt(name string, defaultValue int) int {
+	i, err := strconv.ParseInt(c.PathParam(name), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return int(i)
+}
+
+// Get Path Parameter 64bit integer Value From name
+// If Name Not Found, Return default Value
+func (c *Context) PathParamInt64(name string, defaultValue int64) int64 {
+	i, err := strconv.ParseInt(c.PathParam(name), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return i
+}
+
+// Get Converted Path Parameter Value From name
+// Value of Typed Segment ({id:int}) is Converted Value (int), Others are String.
+// If Name Not Found, Return nil
+func (c *Context) PathValue(name string) interface{} {
+	param, ok := c.pathParam(name)
+	if !ok {
+		return nil
+	}
+	if param.converted == nil {
+		return param.value
+	}
+	return param.converted
+}
+
+// Get All Path Parameters
+func (c *Context) PathParams() map[string]string {
+	params := make(map[string]string, len(c.pathParams))
+	for _, param := range c.pathParams {
+		params[param.key] = param.value
+	}
+	return params
+}
+
+// get path parameter of name (Last one is used if name is duplicated)
+func (c *Context) pathParam(name string) (pathParam, bool) {
+	for i := len(c.pathParams) - 1; i >= 0; i-- {
+		if c.pathParams[i].key == name {
+			return c.pathParams[i], true
+		}
+	}
+	return pathParam{}, false
+}
+
 // Parse query params while tolerating semicolons in values.
 // net/url.ParseQuery rejects semicolons, so treat ';' as literal by escaping it.
 func (c *Context) queryValues() url.Values {
@@ -258,8 +421,7 @@ func (c *Context) Assert(condition bool, message string) error {
 	if condition {
 		return nil
 	}
-	c.SendBadRequest()
-	return errors.New(message)
+	return c.requestError(NewHTTPError(http.StatusBadRequest, message))
 }
 
 // Assert From Integer Close Range
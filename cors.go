@@ -0,0 +1,359 @@
+package gorn
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CORS Policy
+// Router Policy is Set by Router.SetOptions & Can be Overridden per Group or Route
+type CORSPolicy struct {
+	// Allowed Origins (e.g. "https://example.com", "https://*.example.com" or "*")
+	// "*" Can't be Used with AllowCredentials (Credentials are Not Allowed)
+	AllowedOrigins []string
+
+	// Regular Expressions Matching Whole Allowed Origin
+	AllowedOriginRegexps []string
+
+	// Function Deciding Origin is Allowed (Called if Origin Doesn't Match Above)
+	AllowOriginFunc func(origin string, c *Context) bool
+
+	AllowedMethods      []string
+	AllowedHeaders      []string
+	MaxAge              int
+	AllowCredentials    bool
+	AllowPrivateNetwork bool
+
+	// Response Headers Readable by Browser (Access-Control-Expose-Headers)
+	ExposedHeaders []string
+
+	// Pass Accepted Pre-Flight Request to OPTIONS Handler of Route
+	// If Route Has No OPTIONS Handler, No Content (204) is Sent
+	PreflightPassthrough bool
+
+	// Status of Rejected Pre-Flight Request (Default 403)
+	PreflightStatus int
+
+	// Log Why CORS Request is Rejected
+	Debug bool
+
+	// compiled origin patterns
+	allowAll      bool
+	originRegexps []*regexp.Regexp
+	prepared      bool
+}
+
+// default allowed methods of CORS policy
+func defaultCORSMethods() []string {
+	return []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+}
+
+// preparing CORS policy
+// If origin pattern is invalid, panic
+func prepareCORSPolicy(policy *CORSPolicy) *CORSPolicy {
+	if policy != nil && policy.prepared {
+		return policy
+	}
+	prepared := &CORSPolicy{}
+	if policy != nil {
+		*prepared = *policy
+	}
+	if len(prepared.AllowedOrigins) == 0 && len(prepared.AllowedOriginRegexps) == 0 && prepared.AllowOriginFunc == nil {
+		prepared.AllowedOrigins = []string{"*"}
+	}
+	if len(prepared.AllowedMethods) == 0 {
+		prepared.AllowedMethods = defaultCORSMethods()
+	}
+	if prepared.PreflightStatus == 0 {
+		prepared.PreflightStatus = http.StatusForbidden
+	}
+	for _, o := range prepared.AllowedOrigins {
+		if o == "*" {
+			prepared.allowAll = true
+		}
+	}
+	prepared.originRegexps = make([]*regexp.Regexp, 0, len(prepared.AllowedOriginRegexps))
+	for _, pattern := range prepared.AllowedOriginRegexps {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			panic(fmt.Sprintf("gorn: invalid origin pattern '%s': %v", pattern, err))
+		}
+		prepared.originRegexps = append(prepared.originRegexps, re)
+	}
+	if prepared.allowAll && prepared.AllowCredentials {
+		log.Printf("gorn: CORS policy allows any origin (\"*\") with credentials; credentials are not allowed for any origin")
+	}
+	prepared.prepared = true
+	return prepared
+}
+
+// CORS policy of router options
+func (o *RouterOptions) corsPolicy() *CORSPolicy {
+	return prepareCORSPolicy(&CORSPolicy{
+		AllowedOrigins:       o.AllowedOrigins,
+		AllowedOriginRegexps: o.AllowedOriginRegexps,
+		AllowOriginFunc:      o.AllowOriginFunc,
+		AllowedMethods:       o.AllowedMethods,
+		AllowedHeaders:       o.AllowedHeaders,
+		MaxAge:               o.MaxAge,
+		AllowCredentials:     o.AllowCredentials,
+		AllowPrivateNetwork:  o.AllowPrivateNetwork,
+		ExposedHeaders:       o.ExposedHeaders,
+		PreflightPassthrough: o.PreflightPassthrough,
+		PreflightStatus:      o.PreflightStatus,
+		Debug:                o.CORSDebug,
+	})
+}
+
+// Set CORS Policy of Route
+// Policy Takes Precedence Over Policy of Group & Router
+func (rt *Route) CORS(policy *CORSPolicy) *Route {
+	if rt == nil || policy == nil {
+		return rt
+	}
+	rt.cors = prepareCORSPolicy(policy)
+	rt.node.setCORS(rt.method, rt.cors)
+	return rt
+}
+
+// Set CORS Policy of Group
+// Policy is Applied to Routes Registered After Calling CORS
+func (g *Group) CORS(policy *CORSPolicy) *Group {
+	if policy == nil {
+		g.cors = nil
+		return g
+	}
+	g.cors = prepareCORSPolicy(policy)
+	return g
+}
+
+// get CORS policy of node for method
+// If route has no policy, return policy of router
+func (r *Router) corsPolicy(n *node, method string) *CORSPolicy {
+	if policy := n.corsPolicy(strings.ToUpper(method)); policy != nil {
+		return policy
+	}
+	return r.cors
+}
+
+// check is allowed origin
+// Request without origin is not CORS request
+func (p *CORSPolicy) checkOrigin(origin string, c *Context) bool {
+	if origin == "" {
+		return false
+	}
+	if p.allowAll {
+		return true
+	}
+	for _, o := range p.AllowedOrigins {
+		if matchOrigin(o, origin) {
+			return true
+		}
+	}
+	for _, re := range p.originRegexps {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	if p.AllowOriginFunc != nil {
+		return p.AllowOriginFunc(origin, c)
+	}
+	return false
+}
+
+// match origin to allowed origin
+// "https://*.example.com" matches subdomains of any depth (not "https://example.com")
+func matchOrigin(pattern, origin string) bool {
+	i := strings.IndexByte(pattern, '*')
+	if i < 0 {
+		return strings.EqualFold(pattern, origin)
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	if len(origin) <= len(prefix)+len(suffix) {
+		return false
+	}
+	if !strings.EqualFold(origin[:len(prefix)], prefix) || !strings.EqualFold(origin[len(origin)-len(suffix):], suffix) {
+		return false
+	}
+	sub := origin[len(prefix) : len(origin)-len(suffix)]
+	return !strings.ContainsAny(sub, "/:@")
+}
+
+// set allowed origin headers
+// Any origin ("*") is sent as it is without credentials
+func (p *CORSPolicy) setAllowOrigin(c *Context, origin string) {
+	if p.allowAll {
+		c.SetHeader("Access-Control-Allow-Origin", "*")
+		return
+	}
+	c.SetHeader("Access-Control-Allow-Origin", origin)
+	if p.AllowCredentials {
+		c.SetHeader("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// check is allowed method
+func (p *CORSPolicy) checkMethod(method string) bool {
+	if len(p.AllowedMethods) == 0 {
+		return true
+	}
+	method = strings.ToUpper(method)
+	if method == http.MethodOptions {
+		return true
+	}
+	for _, m := range p.AllowedMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// check is allowed header
+func (p *CORSPolicy) checkHeader(headers []string) bool {
+	if len(p.AllowedHeaders) == 0 {
+		return true
+	}
+	if p.AllowedHeaders[0] == "*" {
+		return true
+	}
+	for _, header := range headers {
+		header = http.CanonicalHeaderKey(header)
+		flag := false
+		for _, h := range p.AllowedHeaders {
+			if h == header {
+				flag = true
+				break
+			}
+		}
+		if !flag {
+			return false
+		}
+	}
+	return true
+}
+
+// parsing header list
+func parseHeaderList(headerList string) []string {
+	n := len(headerList)
+	h := make([]byte, 0, n)
+	toLower := byte('a' - 'A')
+	upper := true
+	t := 0
+	for i := 0; i < n; i++ {
+		if headerList[i] == ',' {
+			t++
+		}
+	}
+	headers := make([]string, 0, t)
+	for i := 0; i < n; i++ {
+		b := headerList[i]
+		switch {
+		case b >= 'a' && b <= 'z':
+			if upper {
+				h = append(h, b-toLower)
+			} else {
+				h = append(h, b)
+			}
+		case b >= 'A' && b <= 'Z':
+			if !upper {
+				h = append(h, b+toLower)
+			} else {
+				h = append(h, b)
+			}
+		case b == '-' || b == '_' || b == '.' || (b >= '0' && b <= '9'):
+			h = append(h, b)
+		}
+
+		if b == ' ' || b == ',' || i == n-1 {
+			if len(h) > 0 {
+				// Flush the found header
+				headers = append(headers, string(h))
+				h = h[:0]
+				upper = true
+			}
+		} else {
+			upper = b == '-' || b == '_'
+		}
+	}
+	return headers
+}
+
+// pre-flight CORS requests
+// If request is rejected, send pre-flight status & return false
+// If request is accepted, set CORS headers & return true (Response is not written)
+func (p *CORSPolicy) preFlight(c *Context) bool {
+	origin := c.GetHeader("Origin")
+	method := c.GetHeader("Access-Control-Request-Method")
+
+	// CORS OPTION METHODS
+	c.AddHeader("Vary", "Origin")
+	c.AddHeader("Vary", "Access-Control-Request-Method")
+	c.AddHeader("Vary", "Access-Control-Request-Headers")
+	if p.AllowPrivateNetwork {
+		c.AddHeader("Vary", "Access-Control-Request-Private-Network")
+	}
+	headers := parseHeaderList(c.GetHeader("Access-Control-Request-Headers"))
+	if reason := p.rejectReason(c, origin, method, headers); reason != "" {
+		p.logReject(c, "pre-flight", reason)
+		c.SetContextFinish()
+		c.responseWriter.WriteHeader(p.PreflightStatus)
+		return false
+	}
+	c.SetHeader("Access-Control-Allow-Methods", method)
+	if len(headers) > 0 {
+		c.SetHeader("Access-Control-Allow-Headers", strings.Join(headers, ","))
+	}
+	p.setAllowOrigin(c, origin)
+	if p.AllowPrivateNetwork && c.GetHeader("Access-Control-Request-Private-Network") == "true" {
+		c.SetHeader("Access-Control-Allow-Private-Network", "true")
+	}
+	if p.MaxAge > 0 {
+		c.SetHeader("Access-Control-Max-Age", strconv.Itoa(p.MaxAge))
+	}
+	return true
+}
+
+// handle cors rquests
+func (p *CORSPolicy) actualRequest(c *Context) {
+	origin := c.GetHeader("Origin")
+
+	c.AddHeader("Vary", "Origin")
+	if origin == "" {
+		return
+	}
+	if reason := p.rejectReason(c, origin, c.request.Method, nil); reason != "" {
+		p.logReject(c, "request", reason)
+		return
+	}
+	p.setAllowOrigin(c, origin)
+	if len(p.ExposedHeaders) > 0 {
+		c.SetHeader("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+	}
+}
+
+// get reason why CORS request is rejected
+// If request is allowed, return empty string
+func (p *CORSPolicy) rejectReason(c *Context, origin, method string, headers []string) string {
+	if !p.checkOrigin(origin, c) {
+		return fmt.Sprintf("origin '%s' is not allowed", origin)
+	}
+	if !p.checkMethod(method) {
+		return fmt.Sprintf("method '%s' is not allowed", method)
+	}
+	if !p.checkHeader(headers) {
+		return fmt.Sprintf("headers '%s' are not allowed", strings.Join(headers, ","))
+	}
+	return ""
+}
+
+// log rejected CORS request (Only in debug mode)
+func (p *CORSPolicy) logReject(c *Context, kind, reason string) {
+	if p.Debug {
+		log.Printf("gorn: CORS %s rejected in %s %s: %s", kind, c.request.Method, c.request.URL.Path, reason)
+	}
+}
@@ -0,0 +1,76 @@
+package gorn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCORSOrigin(t *testing.T) {
+	wildcard := &RouterOptions{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true}
+	regexps := &RouterOptions{AllowedOriginRegexps: []string{`https://app-[0-9]+\.example\.com`}}
+	callback := &RouterOptions{AllowOriginFunc: func(origin string, c *Context) bool {
+		return strings.HasSuffix(origin, ".test")
+	}}
+	allowAll := &RouterOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+	tests := []struct {
+		name        string
+		options     *RouterOptions
+		method      string
+		origin      string
+		status      int
+		allowOrigin string
+		credentials string
+	}{
+		{"wildcard subdomain", wildcard, http.MethodGet, "https://api.example.com", http.StatusOK, "https://api.example.com", "true"},
+		{"wildcard nested subdomain", wildcard, http.MethodGet, "https://a.b.example.com", http.StatusOK, "https://a.b.example.com", "true"},
+		{"wildcard case-insensitive", wildcard, http.MethodGet, "HTTPS://API.EXAMPLE.COM", http.StatusOK, "HTTPS://API.EXAMPLE.COM", "true"},
+		{"wildcard without subdomain", wildcard, http.MethodGet, "https://example.com", http.StatusOK, "", ""},
+		{"wildcard other scheme", wildcard, http.MethodGet, "http://api.example.com", http.StatusOK, "", ""},
+		{"wildcard with port", wildcard, http.MethodGet, "https://evil.com:1.example.com", http.StatusOK, "", ""},
+		{"wildcard suffix attack", wildcard, http.MethodGet, "https://api.example.com.evil.com", http.StatusOK, "", ""},
+		{"wildcard pre-flight", wildcard, http.MethodOptions, "https://api.example.com", http.StatusNoContent, "https://api.example.com", "true"},
+		{"wildcard rejected pre-flight", wildcard, http.MethodOptions, "https://example.com", http.StatusForbidden, "", ""},
+		{"regexp", regexps, http.MethodGet, "https://app-12.example.com", http.StatusOK, "https://app-12.example.com", ""},
+		{"regexp matches whole origin", regexps, http.MethodGet, "https://app-12.example.com.evil.com", http.StatusOK, "", ""},
+		{"regexp not matched", regexps, http.MethodGet, "https://app-x.example.com", http.StatusOK, "", ""},
+		{"callback", callback, http.MethodGet, "https://a.test", http.StatusOK, "https://a.test", ""},
+		{"callback rejected", callback, http.MethodGet, "https://a.com", http.StatusOK, "", ""},
+		{"any origin drops credentials", allowAll, http.MethodGet, "https://a.com", http.StatusOK, "*", ""},
+		{"any origin pre-flight drops credentials", allowAll, http.MethodOptions, "https://a.com", http.StatusNoContent, "*", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.SetOptions(tt.options)
+			r.Get("/", func(c *Context) {
+				c.SendPlainText(http.StatusOK, "ok")
+			})
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+			w := serveTestRequest(r, req)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+		})
+	}
+}
+
+func TestCORSInvalidOriginRegexp(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid origin regexp does not panic")
+		}
+	}()
+	NewRouter().SetOptions(&RouterOptions{AllowedOriginRegexps: []string{"("}})
+}
@@ -0,0 +1,130 @@
+package gorn
+
+import (
+	"errors"
+	"log"
+	"net/http"
+)
+
+// HTTP Error Returned From Handlers
+// Rendered by Error Handler of Router
+type HTTPError struct {
+	// HTTP Status Code
+	Status int `json:"status"`
+
+	// Application Error Code
+	Code string `json:"code,omitempty"`
+
+	// Message Sent to Client
+	Message string `json:"message"`
+
+	// Additional Information Sent to Client
+	Details interface{} `json:"details,omitempty"`
+
+	// Internal Cause (Not Sent to Client)
+	Err error `json:"-"`
+}
+
+// Generate a HTTP Error
+// If Message is Empty, Status Text is Used
+func NewHTTPError(status int, message string) *HTTPError {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &HTTPError{
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Set Application Error Code
+func (e *HTTPError) WithCode(code string) *HTTPError {
+	e.Code = code
+	return e
+}
+
+// Set Additional Information
+func (e *HTTPError) WithDetails(details interface{}) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// Set Internal Cause
+func (e *HTTPError) WithErr(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+// Adapt Error Returning Function to Handler
+// Returned Error is Passed to Error Handler of Router.
+// Inside the Function, Binding & Assertion Helpers of Context Don't Send Bad Request (400) Themselves
+// (Handlers Run by c.Next Inside the Function Send Errors Themselves Unless They are Wrapped by WithError)
+func WithError(handler func(c *Context) error) func(c *Context) {
+	return func(c *Context) {
+		returnErrors := c.returnErrors
+		c.returnErrors = true
+		err := handler(c)
+		c.returnErrors = returnErrors
+		if err != nil {
+			c.HandleError(err)
+		}
+	}
+}
+
+// Set Error Handler Rendering Errors Returned From Handlers
+// Default Handler Sends HTTPError with Send Error Functions & Other Errors as Internal Server Error (500)
+func (r *Router) SetErrorHandler(handler func(c *Context, err error)) {
+	r.mustNotServe("error handler is set")
+	r.errorHandler = handler
+}
+
+// Handle Error with Error Handler of Router
+// If Response is Already Written, Error is Not Rendered
+func (c *Context) HandleError(err error) {
+	if err == nil {
+		return
+	}
+	c.Abort()
+	if c.IsWritten() {
+		return
+	}
+	if c.router != nil && c.router.errorHandler != nil {
+		c.router.errorHandler(c, err)
+		return
+	}
+	DefaultErrorHandler(c, err)
+}
+
+// get http error of error
+// If error is not http error, return internal server error
+func toHTTPError(c *Context, err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	log.Printf("gorn: error in %s %s: %v", c.request.Method, c.request.URL.Path, err)
+	return NewHTTPError(http.StatusInternalServerError, "internal server error").WithErr(err)
+}
+
+// Error Handler Sending Error with Error Renderer of Router (See SetErrorRenderer)
+func DefaultErrorHandler(c *Context, err error) {
+	c.SendHTTPError(toHTTPError(c, err))
+}
+
+// Error Handler Sending Json Body
+// {"status": 400, "code": "...", "message": "...", "details": ...}
+func JsonErrorHandler(c *Context, err error) {
+	httpErr := toHTTPError(c, err)
+	c.SendJson(httpErr.Status, httpErr)
+}
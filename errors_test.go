@@ -0,0 +1,89 @@
+package gorn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serve request to router & return recorded response
+func serveTestRequest(r *Router, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// generate json request with body
+func newJsonRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+type bindTestBody struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestWithError(t *testing.T) {
+	bind := func(c *Context) {
+		if c.BindJsonBody(&bindTestBody{}) == nil {
+			c.SendPlainText(http.StatusOK, "ok")
+		}
+	}
+	bindWithError := WithError(func(c *Context) error {
+		if err := c.BindJsonBody(&bindTestBody{}); err != nil {
+			return NewHTTPError(http.StatusTeapot, "returned").WithErr(err)
+		}
+		c.SendPlainText(http.StatusOK, "ok")
+		return nil
+	})
+	passThrough := WithError(func(c *Context) error {
+		c.Next()
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		middleware []func(c *Context)
+		handler    func(c *Context)
+		body       string
+		status     int
+		message    string
+	}{
+		{"plain handler", nil, bind, `{bad`, http.StatusBadRequest, "invalid json"},
+		{"plain handler after error middleware", []func(c *Context){passThrough}, bind, `{bad`, http.StatusBadRequest, "invalid json"},
+		{"validation after error middleware", []func(c *Context){passThrough}, bind, `{}`, http.StatusUnprocessableEntity, "name: is required"},
+		{"error handler", nil, bindWithError, `{bad`, http.StatusTeapot, "returned"},
+		{"error handler after error middleware", []func(c *Context){passThrough}, bindWithError, `{bad`, http.StatusTeapot, "returned"},
+		{"valid body", []func(c *Context){passThrough}, bind, `{"name":"kim"}`, http.StatusOK, "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.Use(tt.middleware...)
+			r.Post("/", tt.handler)
+			w := serveTestRequest(r, newJsonRequest(http.MethodPost, "/", tt.body))
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("got %d %q, want %d containing %q", w.Code, w.Body.String(), tt.status, tt.message)
+			}
+		})
+	}
+}
+
+func TestWithErrorRestoresFlag(t *testing.T) {
+	r := NewRouter()
+	var inside, after bool
+	r.Use(func(c *Context) {
+		c.Next()
+		after = c.returnErrors
+	})
+	r.Get("/", WithError(func(c *Context) error {
+		inside = c.returnErrors
+		return nil
+	}))
+	serveTestRequest(r, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !inside || after {
+		t.Errorf("returnErrors inside = %v, after = %v, want true, false", inside, after)
+	}
+}
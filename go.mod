@@ -1,5 +1,5 @@
 module github.com/thak1411/gorn
 
-go 1.17
+go 1.24
 
 require github.com/go-sql-driver/mysql v1.7.0
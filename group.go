@@ -0,0 +1,99 @@
+package gorn
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Route Group
+// Every Route Registered in Group is Prefixed & Run Group Middleware First
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []func(c *Context)
+	cors       *CORSPolicy
+}
+
+// Generate a Route Group
+func (r *Router) Group(prefix string, middleware ...func(c *Context)) *Group {
+	return &Group{
+		router:     r,
+		prefix:     path.Join("/", prefix),
+		middleware: combineHandler(middleware),
+	}
+}
+
+// Generate a Nested Route Group
+func (g *Group) Group(prefix string, middleware ...func(c *Context)) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     path.Join(g.prefix, prefix),
+		middleware: combineHandler(g.middleware, middleware),
+		cors:       g.cors,
+	}
+}
+
+// Regist Group Middleware
+// Middleware is Applied to Routes Registered After Calling Use
+func (g *Group) Use(middleware ...func(c *Context)) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// regist route to group
+func (g *Group) addRoute(method, p string, handler []func(c *Context)) *Route {
+	if len(handler) < 1 {
+		return nil
+	}
+	return g.router.addRoute(method, joinPath(g.prefix, p), combineHandler(g.middleware, handler), len(g.middleware)).CORS(g.cors)
+}
+
+// Extends Router to Group
+func (g *Group) Extends(prefix string, router *Router) {
+	g.router.extendRoutes(path.Join(g.prefix, prefix), g.middleware, router, g.cors)
+}
+
+// Regist Function of Method to Group
+func (g *Group) Handle(method, path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(strings.ToUpper(method), path, handler)
+}
+
+// Regist Get Function to Group
+func (g *Group) Get(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodGet, path, handler)
+}
+
+// Regist Post Function to Group
+func (g *Group) Post(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodPost, path, handler)
+}
+
+// Regist Put Function to Group
+func (g *Group) Put(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodPut, path, handler)
+}
+
+// Regist Delete Function to Group
+func (g *Group) Delete(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodDelete, path, handler)
+}
+
+// Regist Patch Function to Group
+func (g *Group) Patch(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodPatch, path, handler)
+}
+
+// Regist Head Function to Group
+func (g *Group) Head(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodHead, path, handler)
+}
+
+// Regist Options Function to Group
+func (g *Group) Options(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(http.MethodOptions, path, handler)
+}
+
+// Regist Any Function to Group
+func (g *Group) Any(path string, handler ...func(c *Context)) *Route {
+	return g.addRoute(methodAny, path, handler)
+}
@@ -0,0 +1,167 @@
+package gorn
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Sub-Router Matching Only Requests for Host Pattern
+// Only Routes & Middleware are Registered to Host Router.
+// Options, Error Functions & Not Found Functions of Parent Router are Used.
+type HostRouter struct {
+	pattern string
+	labels  []string
+	router  *Router
+}
+
+// Generate a Sub-Router Matching Only Requests for Host Pattern
+// Pattern Labels in Braces Capture Host Parameter (e.g. "{tenant}.example.com", c.HostParam("tenant")).
+// If Pattern Has No Port, Port of Request Host is Ignored.
+// Routes of Router Itself Match Every Host & are Used When No Host Route Matches.
+// Pattern with Fewer Host Parameters is Matched First
+func (r *Router) Host(pattern string) *HostRouter {
+	pattern = strings.ToLower(pattern)
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return h
+		}
+	}
+	r.mustNotServe(fmt.Sprintf("host router '%s' is registered", pattern))
+	labels := strings.Split(pattern, ".")
+	for _, label := range labels {
+		if strings.HasPrefix(label, "{") != strings.HasSuffix(label, "}") || label == "{}" {
+			panic(fmt.Sprintf("gorn: invalid host pattern '%s'", pattern))
+		}
+	}
+	sub := NewRouter()
+	sub.names = r.names
+	sub.host = true
+	h := &HostRouter{
+		pattern: pattern,
+		labels:  labels,
+		router:  sub,
+	}
+	r.hosts = append(r.hosts, h)
+	// match pattern with fewer host parameters first
+	sort.SliceStable(r.hosts, func(i, j int) bool {
+		return r.hosts[i].paramCount() < r.hosts[j].paramCount()
+	})
+	return h
+}
+
+// Regist Middleware of Host Router
+// Middleware is Run After Router-Wide Middleware of Parent Router
+func (h *HostRouter) Use(middleware ...func(c *Context)) {
+	h.router.Use(middleware...)
+}
+
+// Regist Function of Method to Host Router
+func (h *HostRouter) Handle(method, path string, handler ...func(c *Context)) *Route {
+	return h.router.Handle(method, path, handler...)
+}
+
+// Regist Get Function to Host Router
+func (h *HostRouter) Get(path string, handler ...func(c *Context)) *Route {
+	return h.router.Get(path, handler...)
+}
+
+// Regist Post Function to Host Router
+func (h *HostRouter) Post(path string, handler ...func(c *Context)) *Route {
+	return h.router.Post(path, handler...)
+}
+
+// Regist Put Function to Host Router
+func (h *HostRouter) Put(path string, handler ...func(c *Context)) *Route {
+	return h.router.Put(path, handler...)
+}
+
+// Regist Delete Function to Host Router
+func (h *HostRouter) Delete(path string, handler ...func(c *Context)) *Route {
+	return h.router.Delete(path, handler...)
+}
+
+// Regist Patch Function to Host Router
+func (h *HostRouter) Patch(path string, handler ...func(c *Context)) *Route {
+	return h.router.Patch(path, handler...)
+}
+
+// Regist Head Function to Host Router
+func (h *HostRouter) Head(path string, handler ...func(c *Context)) *Route {
+	return h.router.Head(path, handler...)
+}
+
+// Regist Options Function to Host Router
+func (h *HostRouter) Options(path string, handler ...func(c *Context)) *Route {
+	return h.router.Options(path, handler...)
+}
+
+// Regist Any Function to Host Router
+func (h *HostRouter) Any(path string, handler ...func(c *Context)) *Route {
+	return h.router.Any(path, handler...)
+}
+
+// Generate a Route Group of Host Router
+func (h *HostRouter) Group(prefix string, middleware ...func(c *Context)) *Group {
+	return h.router.Group(prefix, middleware...)
+}
+
+// Extends Router to Host Router
+// If Router Has Host Routers, panic
+func (h *HostRouter) Extends(prefix string, router *Router) {
+	h.router.Extends(prefix, router)
+}
+
+// Serve Files of Directory Under Prefix of Host Router
+func (h *HostRouter) Static(prefix, dir string, options ...*StaticOptions) *Route {
+	return h.router.Static(prefix, dir, options...)
+}
+
+// Serve Files of File System (e.g. embed.FS) Under Prefix of Host Router
+func (h *HostRouter) StaticFS(prefix string, fsys fs.FS, options ...*StaticOptions) *Route {
+	return h.router.StaticFS(prefix, fsys, options...)
+}
+
+// count host parameters of pattern
+func (h *HostRouter) paramCount() int {
+	return strings.Count(h.pattern, "{")
+}
+
+// match host to pattern & extract host parameters
+func (h *HostRouter) match(host string) (map[string]string, bool) {
+	host = strings.ToLower(host)
+	if !strings.Contains(h.pattern, ":") {
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, label := range h.labels {
+		if strings.HasPrefix(label, "{") {
+			if labels[i] == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[label[1:len(label)-1]] = labels[i]
+			continue
+		}
+		if label != labels[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Get Host Parameter Value From name
+// If Name Not Found, Return Empty String
+func (c *Context) HostParam(name string) string {
+	return c.hostParams[name]
+}
@@ -0,0 +1,200 @@
+package gorn
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Offered Representation of Response
+type Offer struct {
+	// Media Type of Registered Encoder (e.g. "application/json")
+	MediaType string
+
+	// Value Encoded by Encoder of Media Type
+	Value interface{}
+}
+
+// encoder of media type
+type encoder struct {
+	mediaType string
+	encode    func(w io.Writer, v interface{}) error
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   []*encoder
+)
+
+func init() {
+	RegisterEncoder("application/json", func(w io.Writer, v interface{}) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+	RegisterEncoder("application/xml", encodeXML)
+	RegisterEncoder("text/plain", func(w io.Writer, v interface{}) error {
+		switch value := v.(type) {
+		case []byte:
+			_, err := w.Write(value)
+			return err
+		case fmt.Stringer:
+			_, err := io.WriteString(w, value.String())
+			return err
+		}
+		_, err := fmt.Fprint(w, v)
+		return err
+	})
+}
+
+// Regist Encoder of Media Type Used by Context.Send & Context.Negotiate
+// Encoders are Preferred in Registration Order if Client Accepts Them Equally
+func RegisterEncoder(mediaType string, encode func(w io.Writer, v interface{}) error) {
+	mediaType = strings.ToLower(mediaType)
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	for _, e := range encoders {
+		if e.mediaType == mediaType {
+			e.encode = encode
+			return
+		}
+	}
+	encoders = append(encoders, &encoder{mediaType, encode})
+}
+
+// get encoder of media type
+func lookupEncoder(mediaType string) (*encoder, bool) {
+	mediaType = strings.ToLower(mediaType)
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	for _, e := range encoders {
+		if e.mediaType == mediaType {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// encode value to xml document
+func encodeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// Send Value Encoded by Encoder Client Accepts Most (See RegisterEncoder)
+// If Client Accepts No Encoder, Send Not Acceptable (406)
+func (c *Context) Send(status int, v interface{}) {
+	encodersMu.RLock()
+	offers := make([]Offer, 0, len(encoders))
+	for _, e := range encoders {
+		offers = append(offers, Offer{e.mediaType, v})
+	}
+	encodersMu.RUnlock()
+	c.Negotiate(status, offers...)
+}
+
+// Send Offer Client Accepts Most by Accept Header
+// Offers are Preferred in Order if Client Accepts Them Equally.
+// Offers of Media Type Without Encoder are Skipped (If Every Offer is Skipped, Send Internal Server Error (500)).
+// If Client Accepts No Offer, Send Not Acceptable (406)
+func (c *Context) Negotiate(status int, offers ...Offer) {
+	c.AddHeader("Vary", "Accept")
+	accept := c.GetHeader("Accept")
+	var best *encoder
+	var bestValue interface{}
+	bestQ, encodable := 0.0, false
+	for _, offer := range offers {
+		e, ok := lookupEncoder(offer.MediaType)
+		if !ok {
+			log.Printf("gorn: no encoder for media type '%s' offered in %s %s", offer.MediaType, c.request.Method, c.request.URL.Path)
+			continue
+		}
+		encodable = true
+		if q := acceptQuality(accept, e.mediaType); q > bestQ {
+			best, bestValue, bestQ = e, offer.Value, q
+		}
+	}
+	if !encodable {
+		c.SendInternalServerError()
+		return
+	}
+	if best == nil {
+		c.SendError(http.StatusNotAcceptable, "not acceptable")
+		return
+	}
+	c.sendEncoded(status, best.mediaType, best.encode, bestValue)
+}
+
+// get quality of media type by accept header
+// Most specific media range is used (e.g. "text/plain" > "text/*" > "*/*")
+func acceptQuality(accept, mediaType string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		value, quality := parseAcceptPart(part)
+		value = strings.ToLower(value)
+		s := -1
+		switch {
+		case value == mediaType:
+			s = 2
+		case strings.HasSuffix(value, "/*") && strings.HasPrefix(mediaType, value[:len(value)-1]):
+			s = 1
+		case value == "*/*" || value == "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = quality, s
+		}
+	}
+	return q
+}
+
+// encode value & send it
+// Value is encoded before writing header, so encoding error is sent as internal server error (500)
+func (c *Context) sendEncoded(status int, contentType string, encode func(w io.Writer, v interface{}) error, v interface{}) {
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		c.SendInternalServerError()
+		return
+	}
+	c.SetContextFinish()
+	c.responseWriter.Header().Set("Content-Type", contentType)
+	c.responseWriter.WriteHeader(status)
+	c.responseWriter.Write(buf.Bytes())
+}
+
+// Send XML Document
+func (c *Context) SendXML(status int, v interface{}) {
+	c.sendEncoded(status, "application/xml", encodeXML, v)
+}
+
+// Binding Body to XML Object & Validate It (See Validate)
+// If Body Can't Decode to XML Object, Send Bad Request (400) & Return Error
+func (c *Context) BindXML(obj interface{}) error {
+	if err := c.decodeXML(obj); err != nil {
+		return c.requestError(err)
+	}
+	return c.Validate(obj)
+}
+
+// decode xml body to object
+func (c *Context) decodeXML(obj interface{}) *HTTPError {
+	if err := xml.NewDecoder(c.request.Body).Decode(obj); err != nil {
+		if httpErr := tooLargeError(err); httpErr != nil {
+			return httpErr
+		}
+		if err == io.EOF {
+			return NewHTTPError(http.StatusBadRequest, "empty xml body").WithErr(err)
+		}
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid xml: %s", strings.TrimPrefix(err.Error(), "xml: "))).WithErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package gorn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []Offer{
+		{"application/json", map[string]string{"a": "b"}},
+		{"text/html", "<p>"}, // no encoder
+		{"text/plain", "plain"},
+	}
+	tests := []struct {
+		name        string
+		offers      []Offer
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"no accept header", offers, "", http.StatusOK, "application/json", `{"a":"b"}`},
+		{"exact", offers, "text/plain", http.StatusOK, "text/plain", "plain"},
+		{"quality", offers, "application/json;q=0.5, text/*", http.StatusOK, "text/plain", "plain"},
+		{"offer without encoder", offers, "text/html", http.StatusNotAcceptable, "text/plain", "not acceptable"},
+		{"offer without encoder is skipped", offers, "text/html, text/plain;q=0.1", http.StatusOK, "text/plain", "plain"},
+		{"every offer without encoder", offers[1:2], "text/html", http.StatusInternalServerError, "text/plain", "internal server error"},
+		{"not acceptable", offers, "image/png", http.StatusNotAcceptable, "text/plain", "not acceptable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.Get("/", func(c *Context) {
+				c.Negotiate(http.StatusOK, tt.offers...)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := serveTestRequest(r, req)
+			if w.Code != tt.status || !strings.HasPrefix(w.Header().Get("Content-Type"), tt.contentType) || !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("got %d %s %q, want %d %s containing %q", w.Code, w.Header().Get("Content-Type"), w.Body.String(), tt.status, tt.contentType, tt.body)
+			}
+			if !strings.Contains(strings.Join(w.Header().Values("Vary"), ","), "Accept") {
+				t.Errorf("Vary = %q, want Accept", w.Header().Values("Vary"))
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package gorn
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery Middleware
+// Catch Panic in Handler Chain, Log Stack Trace & Call Panic Handlers.
+// If Nothing is Written After Panic Handlers, Send Internal Server Error (500)
+func Recovery(handler ...func(c *Context, err interface{})) func(c *Context) {
+	return func(c *Context) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+			log.Printf("gorn: panic recovered in %s %s: %v\n%s", c.request.Method, c.request.URL.Path, err, debug.Stack())
+			c.Abort()
+			for _, h := range handler {
+				h(c, err)
+			}
+			if !c.IsWritten() {
+				c.SendInternalServerError()
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,218 @@
+package gorn
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Registered Route
+type Route struct {
+	router  *Router
+	method  string
+	path    string
+	handler []func(c *Context)
+	name    string
+
+	// count of leading handlers which are middleware
+	middleware int
+
+	// routing tree node of route
+	node *node
+
+	// CORS policy of route (nil if router policy is used)
+	cors *CORSPolicy
+
+	// max body size of route (0 if router limit is used)
+	maxBodySize int64
+
+	// route of extended router this route is copied from (nil if route is registered directly)
+	origin *Route
+}
+
+// Route Information
+type RouteInfo struct {
+	Host       string   `json:"host,omitempty"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Handlers   []string `json:"handlers"`
+	Middleware int      `json:"middleware"`
+}
+
+// Name Route for Reverse URL Generation
+// Name is Kept When Router is Extended. If Router is Extended Multiple Times, First One Keeps Name.
+// If Name is Already Used, panic
+func (rt *Route) Name(name string) *Route {
+	if rt == nil || name == "" {
+		return rt
+	}
+	if prev, ok := rt.router.names[name]; ok && prev != rt {
+		panic(fmt.Sprintf("gorn: route name '%s' is already used by '%s %s'", name, methodName(prev.method), prev.path))
+	}
+	rt.name = name
+	rt.router.names[name] = rt
+	return rt
+}
+
+// copy name of route of extended router
+// If same route is extended again, name is kept by route copied first
+func (rt *Route) extendName(src *Route) *Route {
+	if rt == nil {
+		return rt
+	}
+	rt.origin = src
+	if src.origin != nil {
+		rt.origin = src.origin
+	}
+	if prev, ok := rt.router.names[src.name]; ok && prev.origin == rt.origin {
+		return rt
+	}
+	return rt.Name(src.name)
+}
+
+// Get Information of Every Route Sorted by Host, Path & Method
+// Handlers Include Router-Wide Middleware
+func (r *Router) Routes() []RouteInfo {
+	infos := r.routeInfos("", r.middleware)
+	for _, h := range r.hosts {
+		infos = append(infos, h.router.routeInfos(h.pattern, combineHandler(r.middleware, h.router.middleware))...)
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Host != infos[j].Host {
+			return infos[i].Host < infos[j].Host
+		}
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		return infos[i].Method < infos[j].Method
+	})
+	return infos
+}
+
+// get information of routes registered to router
+func (r *Router) routeInfos(host string, middleware []func(c *Context)) []RouteInfo {
+	infos := make([]RouteInfo, 0, len(r.routes))
+	for _, rt := range r.routes {
+		handler := combineHandler(middleware, rt.handler)
+		names := make([]string, 0, len(handler))
+		for _, h := range handler {
+			names = append(names, handlerName(h))
+		}
+		infos = append(infos, RouteInfo{
+			Host:       host,
+			Method:     methodName(rt.method),
+			Path:       rt.path,
+			Name:       rt.name,
+			Handlers:   names,
+			Middleware: len(middleware) + rt.middleware,
+		})
+	}
+	return infos
+}
+
+// Print Route Table
+// METHOD  HOST+PATH  NAME  HANDLER (+N middleware)
+func (r *Router) PrintRoutes(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, info := range r.Routes() {
+		name := info.Name
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s%s\t%s\t%s (+%d middleware)\n", info.Method, info.Host, info.Path, name, info.Handlers[len(info.Handlers)-1], info.Middleware)
+	}
+	tw.Flush()
+}
+
+// Handler Sending Route Table as Json (For Debug Endpoint)
+func (r *Router) RoutesHandler() func(c *Context) {
+	return func(c *Context) {
+		c.SendJson(http.StatusOK, r.Routes())
+	}
+}
+
+// get function name of handler
+func handlerName(h func(c *Context)) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return fn.Name()
+}
+
+// Generate URL Path of Named Route
+// Params are Key-Value Pairs of Path Parameters (e.g. "id", "42").
+// Params Not Used in Path are Appended as Query String
+func (r *Router) URL(name string, params ...string) (string, error) {
+	rt, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("gorn: route '%s' not found", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("gorn: odd number of params for route '%s'", name)
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	used := make(map[string]bool, len(values))
+	segs := splitPattern(rt.path)
+	for i, seg := range segs {
+		kind, key, constraint := parseSegment(seg)
+		if kind == segmentStatic {
+			segs[i] = url.PathEscape(seg)
+			continue
+		}
+		value, ok := values[key]
+		if !ok || (kind == segmentParam && value == "") {
+			return "", fmt.Errorf("gorn: missing param '%s' for route '%s'", key, name)
+		}
+		if constraint != "" {
+			segType, err := lookupSegmentType(constraint)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := segType.match(value); !ok {
+				return "", fmt.Errorf("gorn: param '%s' of route '%s' does not match '%s'", key, name, constraint)
+			}
+		}
+		used[key] = true
+		if kind == segmentWildcard {
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segs[i] = strings.Join(parts, "/")
+		} else {
+			segs[i] = url.PathEscape(value)
+		}
+	}
+	u := "/" + strings.Join(segs, "/")
+
+	query := url.Values{}
+	for key, value := range values {
+		if !used[key] {
+			query.Set(key, value)
+		}
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u, nil
+}
+
+// Generate URL Path of Named Route (See Router.URL)
+func (c *Context) URLFor(name string, params ...string) (string, error) {
+	if c.router == nil {
+		return "", fmt.Errorf("gorn: route '%s' not found", name)
+	}
+	return c.router.URL(name, params...)
+}
@@ -3,100 +3,284 @@ package gorn
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
-	"os/signal"
 	"path"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type Router struct {
-	mux           *http.ServeMux
-	handler       map[string]bool
-	getHandler    map[string][]func(c *Context)
-	postHandler   map[string][]func(c *Context)
-	putHandler    map[string][]func(c *Context)
-	deleteHandler map[string][]func(c *Context)
-	anyHandler    map[string][]func(c *Context)
-	options       *RouterOptions
+	tree       *node
+	routes     []*Route
+	names      map[string]*Route
+	hosts      []*HostRouter
+	middleware []func(c *Context)
+	options    *RouterOptions
+	cors       *CORSPolicy
+	onShutdown []func()
+
+	// error handlers
+	notFound         []func(c *Context)
+	methodNotAllowed []func(c *Context)
+	errorRenderer    func(c *Context, err *HTTPError)
+	errorHandler     func(c *Context, err error)
+
+	// router is host router of parent router
+	host bool
+
+	// routing table is prepared once on first request
+	// (prepared is read by configuration called concurrently with serving)
+	prepareOnce sync.Once
+	prepared    atomic.Bool
 }
 
 type RouterOptions struct {
-	AllowedOrigins      []string
-	AllowedMethods      []string
-	AllowedHeaders      []string
-	MaxAge              int
-	AllowCredentials    bool
-	AllowPrivateNetwork bool
-}
-
-// copy handler
-func copyHandler(
-	prefix string,
-	rootHandler map[string]bool,
-	destHandler map[string][]func(c *Context),
-	srcHandler map[string][]func(c *Context),
-) {
-	for p, handler := range srcHandler {
-		newPath := path.Join(prefix, p)
-		rootHandler[newPath] = true
-		destHandler[newPath] = handler
+	// Router-Wide CORS Policy (See CORSPolicy)
+	AllowedOrigins       []string
+	AllowedOriginRegexps []string
+	AllowOriginFunc      func(origin string, c *Context) bool
+	AllowedMethods       []string
+	AllowedHeaders       []string
+	MaxAge               int
+	AllowCredentials     bool
+	AllowPrivateNetwork  bool
+	ExposedHeaders       []string
+	PreflightPassthrough bool
+	PreflightStatus      int
+	CORSDebug            bool
+
+	// Print Route Table When Router Starts Serving
+	PrintRoutes bool
+
+	// Trailing Slash Policy (Default TrailingSlashStrict)
+	TrailingSlash TrailingSlashPolicy
+
+	// Status of Redirect to Canonical Path (301, 308)
+	// Default 301 for GET & HEAD, 308 for Others
+	RedirectStatus int
+
+	// Clean "//", "." & ".." Segments of Request Path
+	// With TrailingSlashRedirect, Request is Redirected to Cleaned Path
+	CleanPath bool
+
+	// Match Static Segments Case-Insensitively
+	CaseInsensitive bool
+
+	// Max Size of Request Body (Zero Means No Limit)
+	// Larger Body is Rejected with Request Entity Too Large (413)
+	MaxBodySize int64
+
+	// Decode Json Body Strictly (See Context.BindJsonBodyStrict)
+	StrictJson bool
+
+	// Upload Limits of Multipart Form (Zero Means No Limit)
+	// Type of File is Sniffed From Content (e.g. "image/png", "image/*")
+	MaxUploadSize    int64
+	MaxFileSize      int64
+	MaxFiles         int
+	AllowedFileTypes []string
+
+	// Max Memory of Multipart Form, Rest is Stored on Disk (Default 32MB)
+	MultipartMemory int64
+}
+
+type TrailingSlashPolicy int
+
+const (
+	// "/a" & "/a/" are Different Paths
+	TrailingSlashStrict TrailingSlashPolicy = iota
+
+	// Request is Redirected to Registered Path if Only Trailing Slash Differs
+	TrailingSlashRedirect
+
+	// Request is Served by Registered Path if Only Trailing Slash Differs
+	TrailingSlashTolerant
+)
+
+// regist route to router
+// middleware is count of leading handlers which are middleware
+func (r *Router) addRoute(method, p string, handler []func(c *Context), middleware int) *Route {
+	if len(handler) < 1 {
+		return nil
+	}
+	r.mustNotServe(fmt.Sprintf("route '%s %s' is registered", methodName(method), p))
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
 	}
+	rt := &Route{
+		router:     r,
+		method:     method,
+		path:       p,
+		handler:    handler,
+		middleware: middleware,
+		node:       r.tree.insert(p, method, handler),
+	}
+	r.routes = append(r.routes, rt)
+	return rt
+}
+
+// combine handler lists to new list
+func combineHandler(handlers ...[]func(c *Context)) []func(c *Context) {
+	n := 0
+	for _, h := range handlers {
+		n += len(h)
+	}
+	combined := make([]func(c *Context), 0, n)
+	for _, h := range handlers {
+		combined = append(combined, h...)
+	}
+	return combined
+}
+
+// join path to prefix keeping trailing slash of path
+func joinPath(prefix, p string) string {
+	joined := path.Join(prefix, p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// clean escaped path keeping trailing slash
+func cleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
 }
 
 // Extends Router
+// Middleware of Extended Router is Prepended to Every Route of it.
+// Routes of Host Routers of Extended Router are Copied to Host Routers of Same Pattern
 func (r *Router) Extends(prefix string, router *Router) {
-	prefix = "/" + prefix
-	copyHandler(prefix, r.handler, r.getHandler, router.getHandler)
-	copyHandler(prefix, r.handler, r.postHandler, router.postHandler)
-	copyHandler(prefix, r.handler, r.putHandler, router.putHandler)
-	copyHandler(prefix, r.handler, r.deleteHandler, router.deleteHandler)
-	copyHandler(prefix, r.handler, r.anyHandler, router.anyHandler)
+	r.extendRoutes("/"+prefix, nil, router, nil)
 }
 
-// Regist Get Function to Router
-func (r *Router) Get(path string, handler ...func(c *Context)) {
-	if len(handler) < 1 {
-		return
+// copy routes of router with prefix, middleware & CORS policy (used if route has no policy)
+// Routes of host routers are copied to host routers of same pattern
+func (r *Router) extendRoutes(prefix string, middleware []func(c *Context), router *Router, cors *CORSPolicy) {
+	if r.host && len(router.hosts) > 0 {
+		panic("gorn: router with host routers can't be extended to host router")
+	}
+	for _, rt := range router.routes {
+		policy := rt.cors
+		if policy == nil {
+			policy = cors
+		}
+		count := len(middleware) + len(router.middleware) + rt.middleware
+		r.addRoute(rt.method, joinPath(prefix, rt.path), combineHandler(middleware, router.middleware, rt.handler), count).extendName(rt).CORS(policy).MaxBodySize(rt.maxBodySize)
+	}
+	for _, h := range router.hosts {
+		r.Host(h.pattern).router.extendRoutes(prefix, combineHandler(middleware, router.middleware), h.router, cors)
 	}
-	r.handler[path] = true
-	r.getHandler[path] = handler
+}
+
+// Regist Router-Wide Middleware
+// Middleware is Run Before Handlers of Every Route (Including Routes Registered Later)
+// Middleware Must be Registered Before Router Starts Serving
+func (r *Router) Use(middleware ...func(c *Context)) {
+	r.mustNotServe("middleware is registered")
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Regist Function of Method to Router
+func (r *Router) Handle(method, path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(strings.ToUpper(method), path, handler, 0)
+}
+
+// Regist Get Function to Router
+func (r *Router) Get(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodGet, path, handler, 0)
 }
 
 // Regist Post Function to Router
-func (r *Router) Post(path string, handler ...func(c *Context)) {
-	if len(handler) < 1 {
-		return
-	}
-	r.handler[path] = true
-	r.postHandler[path] = handler
+func (r *Router) Post(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodPost, path, handler, 0)
 }
 
 // Regist Put Function to Router
-func (r *Router) Put(path string, handler ...func(c *Context)) {
-	if len(handler) < 1 {
-		return
-	}
-	r.handler[path] = true
-	r.putHandler[path] = handler
+func (r *Router) Put(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodPut, path, handler, 0)
 }
 
 // Regist Delete Function to Router
-func (r *Router) Delete(path string, handler ...func(c *Context)) {
-	if len(handler) < 1 {
-		return
-	}
-	r.handler[path] = true
-	r.deleteHandler[path] = handler
+func (r *Router) Delete(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodDelete, path, handler, 0)
+}
+
+// Regist Patch Function to Router
+func (r *Router) Patch(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodPatch, path, handler, 0)
+}
+
+// Regist Head Function to Router
+// Get Function is Used for Head Request if Head Function is Not Registered
+func (r *Router) Head(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodHead, path, handler, 0)
+}
+
+// Regist Options Function to Router
+func (r *Router) Options(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(http.MethodOptions, path, handler, 0)
 }
 
 // Regist Any Function to Router
-func (r *Router) Any(path string, handler ...func(c *Context)) {
-	if len(handler) < 1 {
-		return
+func (r *Router) Any(path string, handler ...func(c *Context)) *Route {
+	return r.addRoute(methodAny, path, handler, 0)
+}
+
+// Regist Not Found (404) Function to Router
+// Default Function Sends Not Found (404)
+func (r *Router) NotFound(handler ...func(c *Context)) {
+	r.mustNotServe("not found function is registered")
+	r.notFound = handler
+}
+
+// Regist Method Not Allowed (405) Function to Router
+// Allow Header is Set Before Function is Called
+// Default Function Sends Method Not Allowed (405)
+func (r *Router) MethodNotAllowed(handler ...func(c *Context)) {
+	r.mustNotServe("method not allowed function is registered")
+	r.methodNotAllowed = handler
+}
+
+// Set Error Renderer Used by Send Error Functions of Context
+// (SendError, SendBadRequest, SendNotFound, ... & Errors Sent by Binding Functions)
+// Default Renderer Sends Plain Text
+func (r *Router) SetErrorRenderer(renderer func(c *Context, err *HTTPError)) {
+	r.mustNotServe("error renderer is set")
+	r.errorRenderer = renderer
+}
+
+// Error Renderer Sending Plain Text
+// Message is Followed by One Line per Field Error (e.g. "password: must be at least 8 (min=8)")
+func PlainErrorRenderer(c *Context, err *HTTPError) {
+	lines := []string{err.Message}
+	switch details := err.Details.(type) {
+	case nil:
+	case FieldErrors:
+		for _, fieldErr := range details {
+			line := fieldErr.Error()
+			if fieldErr.Rule != "" && fieldErr.Param != "" {
+				line += fmt.Sprintf(" (%s=%s)", fieldErr.Rule, fieldErr.Param)
+			} else if fieldErr.Rule != "" {
+				line += fmt.Sprintf(" (%s)", fieldErr.Rule)
+			}
+			lines = append(lines, line)
+		}
+	default:
+		lines = append(lines, fmt.Sprint(details))
 	}
-	r.handler[path] = true
-	r.anyHandler[path] = handler
+	http.Error(c.responseWriter, strings.Join(lines, "\n"), err.Status)
+}
+
+// Error Renderer Sending Json Body
+// {"status": 422, "code": "...", "message": "...", "details": [{"field": "...", "rule": "...", ...}]}
+func JsonErrorRenderer(c *Context, err *HTTPError) {
+	c.SendJson(err.Status, err)
 }
 
 // preparing options
@@ -104,263 +288,198 @@ func prepareOptions(options *RouterOptions) *RouterOptions {
 	if options == nil {
 		options = &RouterOptions{}
 	}
-	if len(options.AllowedOrigins) == 0 {
+	if len(options.AllowedOrigins) == 0 && len(options.AllowedOriginRegexps) == 0 && options.AllowOriginFunc == nil {
 		options.AllowedOrigins = []string{"*"}
 	}
 	if len(options.AllowedMethods) == 0 {
-		options.AllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions}
+		options.AllowedMethods = defaultCORSMethods()
 	}
 	return options
 }
 
 // Set Router Options
 func (r *Router) SetOptions(options *RouterOptions) {
+	r.mustNotServe("options are set")
 	r.options = prepareOptions(options)
+	r.cors = r.options.corsPolicy()
 }
 
-// check is allowed origin
-func (r *Router) checkOrigin(origin string) bool {
-	if len(r.options.AllowedOrigins) == 0 {
-		return true
-	}
-	if r.options.AllowedOrigins[0] == "*" {
-		return true
-	}
-	for _, o := range r.options.AllowedOrigins {
-		if o == origin {
-			return true
-		}
+// panic if router already started serving
+// Router is configured before first request, so later change would be ignored or race with requests
+func (r *Router) mustNotServe(what string) {
+	if r.prepared.Load() {
+		panic(fmt.Sprintf("gorn: %s after router started serving", what))
 	}
-	return false
 }
 
-// check is allowed method
-func (r *Router) checkMethod(method string) bool {
-	if len(r.options.AllowedMethods) == 0 {
-		return true
-	}
-	method = strings.ToUpper(method)
-	if method == http.MethodOptions {
-		return true
-	}
-	for _, m := range r.options.AllowedMethods {
-		if m == method {
-			return true
+// Preparing Router
+// Router-Wide Middleware is Prepended to Handlers of Every Route (Including Routes of Host Routers)
+func (r *Router) prepare() {
+	r.prepareOnce.Do(func() {
+		r.tree.prepare(r.middleware)
+		for _, h := range r.hosts {
+			h.router.prepareOnce.Do(func() {
+				h.router.tree.prepare(combineHandler(r.middleware, h.router.middleware))
+				h.router.prepared.Store(true)
+			})
 		}
-	}
-	return false
+		if len(r.notFound) == 0 {
+			r.notFound = []func(c *Context){(*Context).SendNotFound}
+		}
+		if len(r.methodNotAllowed) == 0 {
+			r.methodNotAllowed = []func(c *Context){(*Context).SendMethodNotAllowed}
+		}
+		if r.options.PrintRoutes {
+			r.PrintRoutes(os.Stdout)
+		}
+		r.notFound = combineHandler(r.middleware, r.notFound)
+		r.methodNotAllowed = combineHandler(r.middleware, r.methodNotAllowed)
+		r.prepared.Store(true)
+	})
 }
 
-// check is allowed header
-func (r *Router) checkHeader(headers []string) bool {
-	if len(r.options.AllowedHeaders) == 0 {
-		return true
-	}
-	if r.options.AllowedHeaders[0] == "*" {
-		return true
-	}
-	for _, header := range headers {
-		header = http.CanonicalHeaderKey(header)
-		flag := false
-		for _, h := range r.options.AllowedHeaders {
-			if h == header {
-				flag = true
-				break
+// Serve HTTP Request (Implements http.Handler)
+// Routing Table is Prepared on First Request, Routes Can't be Registered After That
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.prepare()
+	c := &Context{
+		writer:  statusWriter{ResponseWriter: w},
+		request: req,
+		ctx:     req.Context(),
+		router:  r,
+		index:   -1,
+	}
+	c.responseWriter = &c.writer
+	p := req.URL.EscapedPath()
+	redirect := r.options.TrailingSlash == TrailingSlashRedirect && !isPreflight(req)
+	if r.options.CleanPath {
+		if cleaned := cleanPath(p); cleaned != p {
+			if redirect {
+				r.redirect(c, cleaned)
+				return
 			}
-		}
-		if !flag {
-			return false
+			p = cleaned
 		}
 	}
-	return true
-}
-
-// parsing header list
-func parseHeaderList(headerList string) []string {
-	n := len(headerList)
-	h := make([]byte, 0, n)
-	toLower := byte('a' - 'A')
-	upper := true
-	t := 0
-	for i := 0; i < n; i++ {
-		if headerList[i] == ',' {
-			t++
+	for _, h := range r.hosts {
+		hostParams, ok := h.match(req.Host)
+		if !ok {
+			continue
 		}
-	}
-	headers := make([]string, 0, t)
-	for i := 0; i < n; i++ {
-		b := headerList[i]
-		switch {
-		case b >= 'a' && b <= 'z':
-			if upper {
-				h = append(h, b-toLower)
-			} else {
-				h = append(h, b)
-			}
-		case b >= 'A' && b <= 'Z':
-			if !upper {
-				h = append(h, b+toLower)
-			} else {
-				h = append(h, b)
+		if n, params, canonical := r.findRoute(h.router.tree, p); n != nil {
+			if redirect && canonical != p {
+				r.redirect(c, canonical)
+				return
 			}
-		case b == '-' || b == '_' || b == '.' || (b >= '0' && b <= '9'):
-			h = append(h, b)
-		}
-
-		if b == ' ' || b == ',' || i == n-1 {
-			if len(h) > 0 {
-				// Flush the found header
-				headers = append(headers, string(h))
-				h = h[:0]
-				upper = true
-			}
-		} else {
-			upper = b == '-' || b == '_'
+			c.hostParams = hostParams
+			r.serveNode(c, n, params)
+			return
 		}
 	}
-	return headers
-}
-
-// Preparing Router
-func (r *Router) prepare() {
-	for p := range r.handler {
-		getHandler, hasGetHandler := r.getHandler[p]
-		postHandler, hasPostHandler := r.postHandler[p]
-		putHandler, hasPutHandler := r.putHandler[p]
-		deleteHandler, hasDeleteHandler := r.deleteHandler[p]
-		anyHandler, hasAnyHandler := r.anyHandler[p]
-		r.mux.HandleFunc(p, func(w http.ResponseWriter, req *http.Request) {
-			c := &Context{
-				responseWriter: w,
-				request:        req,
-				ctx:            req.Context(),
-			}
-			if req.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
-				r.preFlight(c)
-			} else {
-				r.actualRequest(c)
-				var handler []func(c *Context)
-				var ok bool
-				handler, ok = anyHandler, hasAnyHandler
-				if !ok {
-					switch req.Method {
-					case http.MethodGet:
-						handler, ok = getHandler, hasGetHandler
-					case http.MethodPost:
-						handler, ok = postHandler, hasPostHandler
-					case http.MethodPut:
-						handler, ok = putHandler, hasPutHandler
-					case http.MethodDelete:
-						handler, ok = deleteHandler, hasDeleteHandler
-					default:
-						ok = false
-					}
-				}
-				if !ok {
-					c.SendMethodNotAllowed()
-					return
-				}
-				for _, h := range handler {
-					if c.IsContextFinish() {
-						break
-					}
-					h(c)
-				}
-			}
-		})
+	n, params, canonical := r.findRoute(r.tree, p)
+	if n == nil {
+		c.handlers = r.notFound
+		c.Next()
+		return
+	}
+	if redirect && canonical != p {
+		r.redirect(c, canonical)
+		return
 	}
+	r.serveNode(c, n, params)
 }
 
-// pre-flight CORS requests
-func (r *Router) preFlight(c *Context) {
-	origin := c.GetHeader("Origin")
+// check request is CORS pre-flight request
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+}
 
-	// CORS OPTION METHODS
-	c.AddHeader("Vary", "Origin")
-	c.AddHeader("Vary", "Access-Control-Request-Method")
-	c.AddHeader("Vary", "Access-Control-Request-Headers")
-	if r.options.AllowPrivateNetwork {
-		c.AddHeader("Vary", "Access-Control-Request-Private-Network")
-	}
-	if !r.checkOrigin(c.GetHeader("Origin")) {
-		c.SetContextFinish()
-		return
-	}
-	if !r.checkMethod(c.GetHeader("Access-Control-Request-Method")) {
-		c.SetContextFinish()
-		return
+// find node of escaped path applying trailing slash policy
+// If only trailing slash differs from registered path, return the other path as canonical path
+func (r *Router) findRoute(tree *node, p string) (*node, []pathParam, string) {
+	if n, params := tree.find(p, r.options.CaseInsensitive); n != nil {
+		return n, params, p
 	}
-	headers := parseHeaderList(c.GetHeader("Access-Control-Request-Headers"))
-	if !r.checkHeader(headers) {
-		c.SetContextFinish()
-		return
+	if r.options.TrailingSlash == TrailingSlashStrict || p == "/" {
+		return nil, nil, ""
 	}
-	c.SetHeader("Access-Control-Allow-Methods", c.GetHeader("Access-Control-Request-Method"))
-	if len(headers) > 0 {
-		c.SetHeader("Access-Control-Allow-Headers", strings.Join(headers, ","))
+	alt := p + "/"
+	if strings.HasSuffix(p, "/") {
+		alt = strings.TrimSuffix(p, "/")
 	}
-	c.SetHeader("Access-Control-Allow-Origin", origin)
-	if r.options.AllowCredentials {
-		c.SetHeader("Access-Control-Allow-Credentials", "true")
+	if n, params := tree.find(alt, r.options.CaseInsensitive); n != nil {
+		return n, params, alt
 	}
-	if r.options.AllowPrivateNetwork && c.GetHeader("Access-Control-Request-Private-Network") == "true" {
-		c.SetHeader("Access-Control-Allow-Private-Network", "true")
+	return nil, nil, ""
+}
+
+// redirect request to canonical escaped path
+func (r *Router) redirect(c *Context, p string) {
+	status := r.options.RedirectStatus
+	if status == 0 {
+		status = http.StatusPermanentRedirect
+		if c.request.Method == http.MethodGet || c.request.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
 	}
-	if r.options.MaxAge > 0 {
-		c.SetHeader("Access-Control-Max-Age", strconv.Itoa(r.options.MaxAge))
+	// prevent protocol-relative url ("//host")
+	p = "/" + strings.TrimLeft(p, "/")
+	u := url.URL{Path: p, RawQuery: c.request.URL.RawQuery}
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		u.Path, u.RawPath = unescaped, p
 	}
-	c.responseWriter.WriteHeader(http.StatusNoContent)
+	c.SetContextFinish()
+	http.Redirect(c.responseWriter, c.request, u.RequestURI(), status)
 }
 
-// handle cors rquests
-func (r *Router) actualRequest(c *Context) {
-	origin := c.GetHeader("Origin")
-
-	c.AddHeader("Vary", "Origin")
-	if !r.checkOrigin(origin) {
+// serve request with handlers of matched node
+func (r *Router) serveNode(c *Context, n *node, params []pathParam) {
+	c.pathParams = params
+	if isPreflight(c.request) {
+		policy := r.corsPolicy(n, c.GetHeader("Access-Control-Request-Method"))
+		if !policy.preFlight(c) {
+			return
+		}
+		handler, ok := n.handler(http.MethodOptions)
+		if !policy.PreflightPassthrough || !ok {
+			c.SetContextFinish()
+			c.responseWriter.WriteHeader(http.StatusNoContent)
+			return
+		}
+		c.handlers = handler
+		c.Next()
 		return
 	}
-	if !r.checkMethod(c.request.Method) {
+	r.corsPolicy(n, c.request.Method).actualRequest(c)
+	if !r.limitBody(c, n) {
 		return
 	}
-	c.SetHeader("Access-Control-Allow-Origin", origin)
-	if r.options.AllowCredentials {
-		c.SetHeader("Access-Control-Allow-Credentials", "true")
+	handler, ok := n.handler(c.request.Method)
+	if !ok {
+		c.SetHeader("Allow", strings.Join(n.allowedMethods(), ", "))
+		c.handlers = r.methodNotAllowed
+		c.Next()
+		return
 	}
+	c.handlers = handler
+	c.Next()
 }
 
 // Running Router
 func (r *Router) Run(port int) error {
-	r.prepare()
-
-	ret := make(chan error, 1)
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
-
-	go func() {
-		err := http.ListenAndServe(fmt.Sprintf(":%d", port), r.mux)
-		ret <- err
-	}()
-
-	select {
-	case err := <-ret:
-		return err
-	case <-interrupt:
-		return nil
-	}
+	return r.RunWithConfig(&ServerConfig{Port: port})
 }
 
 // Generate a Gorn Router
 func NewRouter() *Router {
-	options := &RouterOptions{}
+	options := prepareOptions(&RouterOptions{})
 	return &Router{
-		mux:           http.NewServeMux(),
-		handler:       make(map[string]bool),
-		getHandler:    make(map[string][]func(c *Context)),
-		postHandler:   make(map[string][]func(c *Context)),
-		putHandler:    make(map[string][]func(c *Context)),
-		deleteHandler: make(map[string][]func(c *Context)),
-		anyHandler:    make(map[string][]func(c *Context)),
-		options:       prepareOptions(options),
+		tree:       newNode(),
+		routes:     make([]*Route, 0),
+		names:      make(map[string]*Route),
+		middleware: make([]func(c *Context), 0),
+		options:    options,
+		cors:       options.corsPolicy(),
+		onShutdown: make([]func(), 0),
 	}
 }
@@ -0,0 +1,39 @@
+package gorn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfigureAfterServing(t *testing.T) {
+	handler := func(c *Context) {}
+	tests := []struct {
+		name      string
+		configure func(r *Router, h *HostRouter)
+	}{
+		{"route", func(r *Router, h *HostRouter) { r.Get("/b", handler) }},
+		{"middleware", func(r *Router, h *HostRouter) { r.Use(handler) }},
+		{"not found", func(r *Router, h *HostRouter) { r.NotFound(handler) }},
+		{"method not allowed", func(r *Router, h *HostRouter) { r.MethodNotAllowed(handler) }},
+		{"error renderer", func(r *Router, h *HostRouter) { r.SetErrorRenderer(JsonErrorRenderer) }},
+		{"error handler", func(r *Router, h *HostRouter) { r.SetErrorHandler(DefaultErrorHandler) }},
+		{"options", func(r *Router, h *HostRouter) { r.SetOptions(&RouterOptions{}) }},
+		{"host", func(r *Router, h *HostRouter) { r.Host("www.example.com") }},
+		{"route of host", func(r *Router, h *HostRouter) { h.Get("/b", handler) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.Get("/a", handler)
+			h := r.Host("api.example.com")
+			serveTestRequest(r, httptest.NewRequest(http.MethodGet, "/a", nil))
+			defer func() {
+				if recover() == nil {
+					t.Error("configuration after serving does not panic")
+				}
+			}()
+			tt.configure(r, h)
+		})
+	}
+}
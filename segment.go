@@ -0,0 +1,126 @@
+package gorn
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kind of path pattern segment
+const (
+	segmentStatic = iota
+	segmentParam
+	segmentWildcard
+)
+
+// Segment Type of Constrained Path Parameter ({name:type})
+type segmentType struct {
+	regexp  *regexp.Regexp
+	convert func(value string) (interface{}, error)
+}
+
+var (
+	segmentTypesMu sync.RWMutex
+	segmentTypes   = make(map[string]*segmentType)
+)
+
+func init() {
+	RegisterSegmentType("int", `-?[0-9]+`, func(value string) (interface{}, error) {
+		return strconv.Atoi(value)
+	})
+	RegisterSegmentType("int64", `-?[0-9]+`, func(value string) (interface{}, error) {
+		return strconv.ParseInt(value, 10, 64)
+	})
+	RegisterSegmentType("uint", `[0-9]+`, func(value string) (interface{}, error) {
+		i, err := strconv.ParseUint(value, 10, 0)
+		return uint(i), err
+	})
+	RegisterSegmentType("float", `-?[0-9]+(\.[0-9]+)?`, func(value string) (interface{}, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+	RegisterSegmentType("bool", `true|false|1|0`, func(value string) (interface{}, error) {
+		return strconv.ParseBool(value)
+	})
+	RegisterSegmentType("alpha", `[A-Za-z]+`, nil)
+	RegisterSegmentType("alnum", `[A-Za-z0-9]+`, nil)
+	RegisterSegmentType("slug", `[a-z0-9]+(-[a-z0-9]+)*`, nil)
+	RegisterSegmentType("uuid", `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, func(value string) (interface{}, error) {
+		return strings.ToLower(value), nil
+	})
+	RegisterSegmentType("date", `[0-9]{4}-[0-9]{2}-[0-9]{2}`, func(value string) (interface{}, error) {
+		return time.Parse("2006-01-02", value)
+	})
+}
+
+// Regist Segment Type Used as Path Parameter Constraint ({name:type})
+// Segment Matches if Whole Value Matches Pattern & Convert Returns No Error.
+// Converted Value is Available with Context.PathValue (If Convert is nil, Value is String)
+func RegisterSegmentType(name, pattern string, convert func(value string) (interface{}, error)) {
+	re, err := compileSegmentPattern(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("gorn: invalid pattern of segment type '%s': %v", name, err))
+	}
+	segmentTypesMu.Lock()
+	defer segmentTypesMu.Unlock()
+	segmentTypes[name] = &segmentType{
+		regexp:  re,
+		convert: convert,
+	}
+}
+
+// compile pattern matching whole segment
+func compileSegmentPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// get segment type of constraint
+// Constraint is name of registered segment type or regular expression
+func lookupSegmentType(constraint string) (*segmentType, error) {
+	segmentTypesMu.RLock()
+	t, ok := segmentTypes[constraint]
+	segmentTypesMu.RUnlock()
+	if ok {
+		return t, nil
+	}
+	re, err := compileSegmentPattern(constraint)
+	if err != nil {
+		return nil, err
+	}
+	return &segmentType{regexp: re}, nil
+}
+
+// match value to segment type & convert it
+func (t *segmentType) match(value string) (interface{}, bool) {
+	if !t.regexp.MatchString(value) {
+		return nil, false
+	}
+	if t.convert == nil {
+		return value, true
+	}
+	converted, err := t.convert(value)
+	if err != nil {
+		return nil, false
+	}
+	return converted, true
+}
+
+// parse path pattern segment
+// ":name", "{name}", "{name:constraint}" -> param, "*name" -> wildcard, others -> static
+func parseSegment(seg string) (kind int, name, constraint string) {
+	switch {
+	case strings.HasPrefix(seg, ":"):
+		return segmentParam, seg[1:], ""
+	case strings.HasPrefix(seg, "*"):
+		return segmentWildcard, seg[1:], ""
+	case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+		inner := seg[1 : len(seg)-1]
+		if i := strings.IndexByte(inner, ':'); i >= 0 {
+			return segmentParam, inner[:i], inner[i+1:]
+		}
+		return segmentParam, inner, ""
+	}
+	return segmentStatic, seg, ""
+}
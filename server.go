@@ -0,0 +1,225 @@
+package gorn
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// default grace period of graceful shutdown
+const defaultShutdownTimeout = 10 * time.Second
+
+type ServerConfig struct {
+	// Listen Address (e.g. "127.0.0.1:8080")
+	// If Addr is Empty, Listen on ":{Port}"
+	Addr string
+	Port int
+
+	// http.Server Timeouts & Limits (Zero Means No Limit)
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	// Grace Period for In-Flight Requests on Shutdown (Default 10s)
+	ShutdownTimeout time.Duration
+
+	// Signals Triggering Shutdown (Default SIGINT, SIGTERM)
+	Signals []os.Signal
+
+	// TLS Certificate Files and/or TLS Config
+	// If One of Them is Set, Server Runs on TLS with HTTP/2 Enabled
+	CertFile  string
+	KeyFile   string
+	TLSConfig *tls.Config
+
+	// Serve Cleartext HTTP/2 (h2c) Without TLS
+	H2C bool
+
+	// Listen Address of HTTP Server Redirecting to HTTPS (e.g. ":80")
+	// Only Used When Server Runs on TLS
+	RedirectAddr string
+}
+
+// preparing server config
+func prepareServerConfig(conf *ServerConfig) *ServerConfig {
+	if conf == nil {
+		conf = &ServerConfig{}
+	}
+	prepared := *conf
+	if prepared.Addr == "" {
+		prepared.Addr = fmt.Sprintf(":%d", prepared.Port)
+	}
+	if prepared.ShutdownTimeout <= 0 {
+		prepared.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if len(prepared.Signals) == 0 {
+		prepared.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	return &prepared
+}
+
+// check server runs on tls
+func (conf *ServerConfig) isTLS() bool {
+	return conf.CertFile != "" || conf.TLSConfig != nil
+}
+
+// Regist Function Called After Graceful Shutdown
+// Hooks are Called in Registration Order (e.g. Closing DB Pool)
+func (r *Router) OnShutdown(fn func()) {
+	r.onShutdown = append(r.onShutdown, fn)
+}
+
+// build http server from config
+func (r *Router) newServer(conf *ServerConfig) *http.Server {
+	server := &http.Server{
+		Addr:              conf.Addr,
+		Handler:           r,
+		ReadTimeout:       conf.ReadTimeout,
+		ReadHeaderTimeout: conf.ReadHeaderTimeout,
+		WriteTimeout:      conf.WriteTimeout,
+		IdleTimeout:       conf.IdleTimeout,
+		MaxHeaderBytes:    conf.MaxHeaderBytes,
+		Protocols:         new(http.Protocols),
+	}
+	server.Protocols.SetHTTP1(true)
+	if conf.isTLS() {
+		server.Protocols.SetHTTP2(true)
+		if conf.TLSConfig != nil {
+			server.TLSConfig = conf.TLSConfig.Clone()
+		}
+	}
+	if conf.H2C {
+		server.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return server
+}
+
+// build http server redirecting to https server
+func newRedirectServer(conf *ServerConfig) (*http.Server, error) {
+	_, port, err := net.SplitHostPort(conf.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Server{
+		Addr:              conf.RedirectAddr,
+		ReadTimeout:       conf.ReadTimeout,
+		ReadHeaderTimeout: conf.ReadHeaderTimeout,
+		WriteTimeout:      conf.WriteTimeout,
+		IdleTimeout:       conf.IdleTimeout,
+		MaxHeaderBytes:    conf.MaxHeaderBytes,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			host := req.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			if port != "443" {
+				host = net.JoinHostPort(host, port)
+			}
+			http.Redirect(w, req, "https://"+host+req.URL.RequestURI(), http.StatusMovedPermanently)
+		}),
+	}, nil
+}
+
+// server with function starting it
+type runningServer struct {
+	server *http.Server
+	listen func() error
+}
+
+// run servers until one of them fails or shutdown signal is received
+func (r *Router) runServer(conf *ServerConfig, servers ...*runningServer) error {
+	r.prepare()
+	ret := make(chan error, len(servers))
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, conf.Signals...)
+	defer signal.Stop(interrupt)
+
+	for _, s := range servers {
+		go func() {
+			ret <- s.listen()
+		}()
+	}
+
+	var err error
+	select {
+	case err = <-ret:
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+	case <-interrupt:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.ShutdownTimeout)
+	defer cancel()
+	for _, s := range servers {
+		if shutdownErr := s.server.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	for _, fn := range r.onShutdown {
+		fn()
+	}
+	return err
+}
+
+// Running Router with Server Config
+// On SIGINT or SIGTERM, Wait In-Flight Requests & Call Shutdown Hooks
+func (r *Router) RunWithConfig(conf *ServerConfig) error {
+	conf = prepareServerConfig(conf)
+	server := r.newServer(conf)
+	if !conf.isTLS() {
+		return r.runServer(conf, &runningServer{server, server.ListenAndServe})
+	}
+	servers := []*runningServer{{server, func() error {
+		return server.ListenAndServeTLS(conf.CertFile, conf.KeyFile)
+	}}}
+	if conf.RedirectAddr != "" {
+		redirect, err := newRedirectServer(conf)
+		if err != nil {
+			return err
+		}
+		servers = append(servers, &runningServer{redirect, redirect.ListenAndServe})
+	}
+	return r.runServer(conf, servers...)
+}
+
+// Running Router with TLS
+func (r *Router) RunTLS(port int, certFile, keyFile string) error {
+	return r.RunWithConfig(&ServerConfig{
+		Port:     port,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	})
+}
+
+// Running Router on Listener
+func (r *Router) RunListener(listener net.Listener) error {
+	conf := prepareServerConfig(nil)
+	server := r.newServer(conf)
+	return r.runServer(conf, &runningServer{server, func() error {
+		return server.Serve(listener)
+	}})
+}
+
+// Running Router on Unix Domain Socket
+// Stale Socket File is Removed Before Listening
+func (r *Router) RunUnix(socketPath string) error {
+	if info, err := os.Stat(socketPath); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(socketPath); err != nil {
+			return err
+		}
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	return r.RunListener(listener)
+}
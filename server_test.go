@@ -0,0 +1,158 @@
+package gorn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// generate self-signed certificate of 127.0.0.1
+func newTestCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"gorn test"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+// serve server on local listener until test ends
+func serveTest(t *testing.T, server *http.Server, serve func(l net.Listener) error) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serve(l)
+	t.Cleanup(func() { server.Close() })
+	return l.Addr().String()
+}
+
+// router responding protocol of request
+func newProtoRouter() *Router {
+	r := NewRouter()
+	r.Get("/", func(c *Context) {
+		c.SendPlainText(http.StatusOK, c.GetRequest().Proto)
+	})
+	return r
+}
+
+func TestServerHTTP2OverTLS(t *testing.T) {
+	cert, pool := newTestCert(t)
+	conf := prepareServerConfig(&ServerConfig{TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}}})
+	server := newProtoRouter().newServer(conf)
+	addr := serveTest(t, server, func(l net.Listener) error {
+		return server.ServeTLS(l, "", "")
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+		t.Errorf("got %d over %s, want 200 over HTTP/2.0", resp.StatusCode, resp.Proto)
+	}
+}
+
+func TestServerH2C(t *testing.T) {
+	conf := prepareServerConfig(&ServerConfig{H2C: true})
+	server := newProtoRouter().newServer(conf)
+	addr := serveTest(t, server, server.Serve)
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	resp, err := client.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+		t.Errorf("got %d over %s, want 200 over HTTP/2.0", resp.StatusCode, resp.Proto)
+	}
+}
+
+func TestServerWithoutH2C(t *testing.T) {
+	conf := prepareServerConfig(&ServerConfig{})
+	server := newProtoRouter().newServer(conf)
+	addr := serveTest(t, server, server.Serve)
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	if resp, err := client.Get("http://" + addr + "/"); err == nil {
+		resp.Body.Close()
+		t.Errorf("h2c request is served over %s, want error", resp.Proto)
+	}
+}
+
+func TestServerRedirect(t *testing.T) {
+	tests := []struct {
+		addr     string
+		target   string
+		location string
+	}{
+		{":8443", "/users?page=2", "https://127.0.0.1:8443/users?page=2"},
+		{":443", "/users?page=2", "https://127.0.0.1/users?page=2"},
+		{"127.0.0.1:443", "/a%2Fb", "https://127.0.0.1/a%2Fb"},
+	}
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	for _, tt := range tests {
+		conf := prepareServerConfig(&ServerConfig{Addr: tt.addr, RedirectAddr: "127.0.0.1:0"})
+		server, err := newRedirectServer(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := serveTest(t, server, server.Serve)
+		resp, err := client.Get("http://" + addr + tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMovedPermanently || resp.Header.Get("Location") != tt.location {
+			t.Errorf("redirect of %s to %s = %d %q, want 301 %q", tt.target, tt.addr, resp.StatusCode, resp.Header.Get("Location"), tt.location)
+		}
+	}
+}
+
+func TestRedirectServerInvalidAddr(t *testing.T) {
+	if _, err := newRedirectServer(&ServerConfig{Addr: "8443", RedirectAddr: ":80"}); err == nil {
+		t.Error("redirect server of address without port is built, want error")
+	}
+}
@@ -0,0 +1,287 @@
+package gorn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type StaticOptions struct {
+	// Show Directory Listing if Directory Has No Index File
+	Browse bool
+
+	// Index File Name of Directory (Default "index.html")
+	Index string
+
+	// Cache-Control max-age (Zero Means No Cache-Control Header)
+	MaxAge time.Duration
+
+	// Serve Precompressed Sibling (.br, .gz) if Client Accepts It
+	Precompressed bool
+
+	// Serve Root Index File for Unknown Paths (Single-Page App)
+	SPA bool
+
+	// etags of files without modification time (e.g. embed.FS), keyed by file name
+	etags *sync.Map
+}
+
+// etag of file content with size of content hashed
+type staticETag struct {
+	size int64
+	etag string
+}
+
+// preparing static options
+func prepareStaticOptions(options []*StaticOptions) *StaticOptions {
+	prepared := &StaticOptions{}
+	if len(options) > 0 && options[0] != nil {
+		*prepared = *options[0]
+	}
+	if prepared.Index == "" {
+		prepared.Index = "index.html"
+	}
+	prepared.etags = &sync.Map{}
+	return prepared
+}
+
+// precompressed sibling extensions in order of preference
+var precompressedEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// Serve Files of Directory Under Prefix
+func (r *Router) Static(prefix, dir string, options ...*StaticOptions) *Route {
+	return r.StaticFS(prefix, os.DirFS(dir), options...)
+}
+
+// Serve Files of File System (e.g. embed.FS) Under Prefix
+// Use fs.Sub to Serve Sub Directory of embed.FS
+// Prefix Itself is Redirected to Prefix with Trailing Slash (e.g. "/assets" -> "/assets/")
+func (r *Router) StaticFS(prefix string, fsys fs.FS, options ...*StaticOptions) *Route {
+	if root := path.Join("/", prefix); root != "/" {
+		r.Get(root, redirectDir)
+	}
+	return r.Get(path.Join("/", prefix, "*filepath"), staticHandler(fsys, prepareStaticOptions(options)))
+}
+
+// Serve Files of Directory Under Prefix of Group
+func (g *Group) Static(prefix, dir string, options ...*StaticOptions) *Route {
+	return g.StaticFS(prefix, os.DirFS(dir), options...)
+}
+
+// Serve Files of File System (e.g. embed.FS) Under Prefix of Group
+// Prefix Itself is Redirected to Prefix with Trailing Slash
+func (g *Group) StaticFS(prefix string, fsys fs.FS, options ...*StaticOptions) *Route {
+	if root := path.Join("/", prefix); path.Join(g.prefix, root) != "/" {
+		g.Get(strings.TrimSuffix(root, "/"), redirectDir)
+	}
+	return g.Get(path.Join("/", prefix, "*filepath"), staticHandler(fsys, prepareStaticOptions(options)))
+}
+
+// handler serving file system
+func staticHandler(fsys fs.FS, options *StaticOptions) func(c *Context) {
+	return func(c *Context) {
+		name := strings.TrimPrefix(path.Clean("/"+c.PathParam("filepath")), "/")
+		if name == "" {
+			name = "."
+		}
+		serveStatic(c, fsys, name, options)
+	}
+}
+
+// serve file or directory of file system
+func serveStatic(c *Context, fsys fs.FS, name string, options *StaticOptions) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		if options.SPA && name != "." {
+			serveStaticFile(c, fsys, options.Index, options)
+			return
+		}
+		c.SendNotFound()
+		return
+	}
+	if !info.IsDir() {
+		serveStaticFile(c, fsys, name, options)
+		return
+	}
+
+	// redirect to canonical directory path
+	if !strings.HasSuffix(c.request.URL.Path, "/") {
+		redirectDir(c)
+		return
+	}
+	index := path.Join(name, options.Index)
+	if info, err := fs.Stat(fsys, index); err == nil && !info.IsDir() {
+		serveStaticFile(c, fsys, index, options)
+		return
+	}
+	if options.Browse {
+		serveDirList(c, fsys, name)
+		return
+	}
+	if options.SPA {
+		serveStaticFile(c, fsys, options.Index, options)
+		return
+	}
+	c.SendNotFound()
+}
+
+// redirect request to path with trailing slash
+func redirectDir(c *Context) {
+	u := *c.request.URL
+	u.Path += "/"
+	u.RawPath = ""
+	c.SetContextFinish()
+	http.Redirect(c.responseWriter, c.request, u.String(), http.StatusMovedPermanently)
+}
+
+// serve file (or precompressed sibling) of file system
+func serveStaticFile(c *Context, fsys fs.FS, name string, options *StaticOptions) {
+	served := name
+	if options.Precompressed {
+		accept := c.GetHeader("Accept-Encoding")
+		c.AddHeader("Vary", "Accept-Encoding")
+		for _, p := range precompressedEncodings {
+			if !acceptsEncoding(accept, p.encoding) {
+				continue
+			}
+			if info, err := fs.Stat(fsys, name+p.ext); err == nil && !info.IsDir() {
+				served = name + p.ext
+				c.SetHeader("Content-Encoding", p.encoding)
+				break
+			}
+		}
+	}
+
+	f, err := fsys.Open(served)
+	if err != nil {
+		c.SendNotFound()
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		c.SendInternalServerError()
+		return
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			c.SendInternalServerError()
+			return
+		}
+		content = bytes.NewReader(b)
+	}
+
+	if served != name {
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		c.SetHeader("Content-Type", contentType)
+	}
+	if options.MaxAge > 0 {
+		c.SetHeader("Cache-Control", fmt.Sprintf("public, max-age=%d", int(options.MaxAge.Seconds())))
+	}
+	// file without modification time can't be validated by Last-Modified, so it's validated by etag
+	if info.ModTime().IsZero() && c.responseWriter.Header().Get("ETag") == "" {
+		etag, err := contentETag(options, served, info.Size(), content)
+		if err != nil {
+			c.SendInternalServerError()
+			return
+		}
+		c.SetHeader("ETag", etag)
+	}
+	c.SetContextFinish()
+	http.ServeContent(c.responseWriter, c.request, name, info.ModTime(), content)
+}
+
+// get etag of file content (Hash is cached until size of file is changed)
+func contentETag(options *StaticOptions, name string, size int64, content io.ReadSeeker) (string, error) {
+	if cached, ok := options.etags.Load(name); ok && cached.(*staticETag).size == size {
+		return cached.(*staticETag).etag, nil
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	options.etags.Store(name, &staticETag{size, etag})
+	return etag, nil
+}
+
+// serve directory listing
+func serveDirList(c *Context, fsys fs.FS, name string) {
+	entries, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		c.SendInternalServerError()
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	var b strings.Builder
+	b.WriteString("<!doctype html>\n<pre>\n")
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		u := url.URL{Path: entryName}
+		fmt.Fprintf(&b, "<a href=\"%s\">%s</a>\n", html.EscapeString(u.String()), html.EscapeString(entryName))
+	}
+	b.WriteString("</pre>\n")
+	c.SendHTML(http.StatusOK, b.String())
+}
+
+// check accept-encoding header accepts encoding
+func acceptsEncoding(accept, encoding string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		value, q := parseAcceptPart(part)
+		if strings.EqualFold(value, encoding) {
+			return q > 0
+		}
+	}
+	return false
+}
+
+// parse part of accept header list to value & quality
+// e.g. "gzip;q=0.8" -> "gzip", 0.8
+func parseAcceptPart(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	value := strings.TrimSpace(params[0])
+	q := 1.0
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		if f, err := strconv.ParseFloat(param[2:], 64); err == nil && f >= 0 && f <= 1 {
+			q = f
+		}
+	}
+	return value, q
+}
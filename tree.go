@@ -0,0 +1,375 @@
+package gorn
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// method key of handlers registered by Router.Any
+const methodAny = "*"
+
+// Routing Tree Node
+//
+// Each node is one path segment. Request paths are resolved with
+// deterministic priority: static segment > constrained parameter ({name:type})
+// > parameter (:name) > wildcard (*name). Constrained parameters are tried
+// in registration order. If a branch can not resolve the rest of the path,
+// matching backtracks to the next candidate of lower priority.
+type node struct {
+	// static children by segment
+	static map[string]*node
+
+	// static children by lowercase segment in registration order (for case-insensitive matching)
+	folded map[string][]*node
+
+	// parameter children (constrained first)
+	params []*paramNode
+
+	// wildcard child (*name)
+	wildcard     *node
+	wildcardName string
+
+	// registered path pattern (if node has handlers)
+	pattern string
+
+	// handlers by method
+	handlers map[string][]func(c *Context)
+
+	// CORS policies by method
+	cors map[string]*CORSPolicy
+
+	// max body sizes by method
+	bodyLimits map[string]int64
+}
+
+// Parameter Child of Routing Tree Node
+type paramNode struct {
+	name       string
+	constraint string
+	segType    *segmentType
+	node       *node
+}
+
+type pathParam struct {
+	key   string
+	value string
+
+	// converted value of typed segment (nil for untyped segment)
+	converted interface{}
+}
+
+// Generate a Routing Tree Node
+func newNode() *node {
+	return &node{
+		static: make(map[string]*node),
+	}
+}
+
+// split path pattern to segments
+func splitPattern(pattern string) []string {
+	return strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+}
+
+// insert handlers to tree & return node of pattern
+// If pattern conflicts with registered pattern, panic
+func (n *node) insert(pattern, method string, handler []func(c *Context)) *node {
+	segs := splitPattern(pattern)
+	cur := n
+	for i, seg := range segs {
+		kind, name, constraint := parseSegment(seg)
+		switch kind {
+		case segmentParam:
+			if name == "" {
+				panic(fmt.Sprintf("gorn: empty parameter name in path '%s'", pattern))
+			}
+			cur = cur.paramChild(pattern, name, constraint)
+		case segmentWildcard:
+			if name == "" {
+				panic(fmt.Sprintf("gorn: empty wildcard name in path '%s'", pattern))
+			}
+			if i != len(segs)-1 {
+				panic(fmt.Sprintf("gorn: wildcard '*%s' must be the last segment in path '%s'", name, pattern))
+			}
+			if cur.wildcard == nil {
+				cur.wildcard = newNode()
+				cur.wildcardName = name
+			} else if cur.wildcardName != name {
+				panic(fmt.Sprintf("gorn: wildcard '*%s' in path '%s' conflicts with existing wildcard '*%s'", name, pattern, cur.wildcardName))
+			}
+			cur = cur.wildcard
+		default:
+			child, ok := cur.static[seg]
+			if !ok {
+				child = newNode()
+				cur.static[seg] = child
+				if cur.folded == nil {
+					cur.folded = make(map[string][]*node)
+				}
+				lower := strings.ToLower(seg)
+				cur.folded[lower] = append(cur.folded[lower], child)
+			}
+			cur = child
+		}
+	}
+	if cur.handlers == nil {
+		cur.handlers = make(map[string][]func(c *Context))
+	}
+	if _, ok := cur.handlers[method]; ok {
+		panic(fmt.Sprintf("gorn: route '%s %s' is already registered", methodName(method), pattern))
+	}
+	cur.pattern = pattern
+	cur.handlers[method] = handler
+	return cur
+}
+
+// get or create parameter child of constraint
+// If parameter name conflicts with existing parameter of same constraint, panic
+func (n *node) paramChild(pattern, name, constraint string) *node {
+	for _, p := range n.params {
+		if p.constraint != constraint {
+			continue
+		}
+		if p.name != name {
+			panic(fmt.Sprintf("gorn: parameter '%s' in path '%s' conflicts with existing parameter '%s'", name, pattern, p.name))
+		}
+		return p.node
+	}
+	p := &paramNode{
+		name:       name,
+		constraint: constraint,
+		node:       newNode(),
+	}
+	if constraint == "" {
+		n.params = append(n.params, p)
+		return p.node
+	}
+	segType, err := lookupSegmentType(constraint)
+	if err != nil {
+		panic(fmt.Sprintf("gorn: invalid constraint of parameter '%s' in path '%s': %v", name, pattern, err))
+	}
+	p.segType = segType
+
+	// constrained parameter is placed before unconstrained parameter
+	i := len(n.params)
+	if i > 0 && n.params[i-1].constraint == "" {
+		i--
+	}
+	n.params = append(n.params, nil)
+	copy(n.params[i+1:], n.params[i:])
+	n.params[i] = p
+	return p.node
+}
+
+// find node matching escaped path
+// If foldCase is true, static segments are matched case-insensitively
+// If no node matches, return nil
+func (n *node) find(p string, foldCase bool) (*node, []pathParam) {
+	// params is allocated only when parameter segment matches
+	var params []pathParam
+	found := n.findSegment(strings.TrimPrefix(p, "/"), foldCase, &params)
+	if found == nil {
+		return nil, nil
+	}
+	return found, params
+}
+
+// find node matching rest of escaped path
+func (n *node) findSegment(p string, foldCase bool, params *[]pathParam) *node {
+	seg, rest, hasRest := p, "", false
+	if i := strings.IndexByte(p, '/'); i >= 0 {
+		seg, rest, hasRest = p[:i], p[i+1:], true
+	}
+	value, ok := unescapeSegment(seg)
+	if !ok {
+		return nil
+	}
+
+	// static segment (exact segment first, then other cases in registration order)
+	exact, ok := n.static[value]
+	if ok {
+		if found := exact.next(rest, hasRest, foldCase, params); found != nil {
+			return found
+		}
+	}
+	if foldCase {
+		for _, child := range n.folded[strings.ToLower(value)] {
+			if child == exact {
+				continue
+			}
+			if found := child.next(rest, hasRest, foldCase, params); found != nil {
+				return found
+			}
+		}
+	}
+
+	// parameter segment
+	if value != "" {
+		for _, p := range n.params {
+			var converted interface{}
+			if p.segType != nil {
+				var ok bool
+				if converted, ok = p.segType.match(value); !ok {
+					continue
+				}
+			}
+			if *params == nil {
+				*params = make([]pathParam, 0, 4)
+			}
+			*params = append(*params, pathParam{p.name, value, converted})
+			if found := p.node.next(rest, hasRest, foldCase, params); found != nil {
+				return found
+			}
+			*params = (*params)[:len(*params)-1]
+		}
+	}
+
+	// wildcard segment
+	if n.wildcard != nil && len(n.wildcard.handlers) > 0 {
+		value, ok := unescapeSegment(p)
+		if !ok {
+			return nil
+		}
+		*params = append(*params, pathParam{n.wildcardName, value, nil})
+		return n.wildcard
+	}
+	return nil
+}
+
+// continue matching to child node
+func (n *node) next(rest string, hasRest, foldCase bool, params *[]pathParam) *node {
+	if hasRest {
+		return n.findSegment(rest, foldCase, params)
+	}
+	if len(n.handlers) > 0 {
+		return n
+	}
+	return nil
+}
+
+// visit every node of tree
+func (n *node) walk(fn func(n *node)) {
+	fn(n)
+	for _, child := range n.static {
+		child.walk(fn)
+	}
+	for _, p := range n.params {
+		p.node.walk(fn)
+	}
+	if n.wildcard != nil {
+		n.wildcard.walk(fn)
+	}
+}
+
+// prepend middleware to handlers of every node
+func (n *node) prepare(middleware []func(c *Context)) {
+	n.walk(func(n *node) {
+		for method, handler := range n.handlers {
+			n.handlers[method] = combineHandler(middleware, handler)
+		}
+	})
+}
+
+// get handlers of method
+// Any Function is Used First & Get Function is Used for Head Request
+func (n *node) handler(method string) ([]func(c *Context), bool) {
+	if handler, ok := n.handlers[methodAny]; ok {
+		return handler, true
+	}
+	if handler, ok := n.handlers[method]; ok {
+		return handler, true
+	}
+	if method == http.MethodHead {
+		return n.handlers[http.MethodGet], n.handlers[http.MethodGet] != nil
+	}
+	return nil, false
+}
+
+// set CORS policy of method
+func (n *node) setCORS(method string, policy *CORSPolicy) {
+	if n.cors == nil {
+		n.cors = make(map[string]*CORSPolicy)
+	}
+	n.cors[method] = policy
+}
+
+// get CORS policy of method
+// Policy is looked up in same order as handlers
+func (n *node) corsPolicy(method string) *CORSPolicy {
+	if len(n.cors) == 0 {
+		return nil
+	}
+	if _, ok := n.handlers[methodAny]; ok {
+		return n.cors[methodAny]
+	}
+	if _, ok := n.handlers[method]; ok {
+		return n.cors[method]
+	}
+	if method == http.MethodHead {
+		return n.cors[http.MethodGet]
+	}
+	return nil
+}
+
+// set max body size of method
+func (n *node) setBodyLimit(method string, size int64) {
+	if n.bodyLimits == nil {
+		n.bodyLimits = make(map[string]int64)
+	}
+	n.bodyLimits[method] = size
+}
+
+// get max body size of method (0 if not set)
+// Size is looked up in same order as handlers
+func (n *node) bodyLimit(method string) int64 {
+	if len(n.bodyLimits) == 0 {
+		return 0
+	}
+	if _, ok := n.handlers[methodAny]; ok {
+		return n.bodyLimits[methodAny]
+	}
+	if _, ok := n.handlers[method]; ok {
+		return n.bodyLimits[method]
+	}
+	if method == http.MethodHead {
+		return n.bodyLimits[http.MethodGet]
+	}
+	return 0
+}
+
+// get sorted methods registered to node
+func (n *node) allowedMethods() []string {
+	methods := make([]string, 0, len(n.handlers)+1)
+	for method := range n.handlers {
+		methods = append(methods, method)
+	}
+	if _, ok := n.handlers[http.MethodGet]; ok {
+		if _, ok := n.handlers[http.MethodHead]; !ok {
+			methods = append(methods, http.MethodHead)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// unescape path segment
+func unescapeSegment(seg string) (string, bool) {
+	if strings.IndexByte(seg, '%') < 0 {
+		return seg, true
+	}
+	value, err := url.PathUnescape(seg)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// method name for display
+func methodName(method string) string {
+	if method == methodAny {
+		return "ANY"
+	}
+	return method
+}
@@ -0,0 +1,283 @@
+package gorn
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func noop(c *Context) {}
+
+func newTestTree(patterns ...string) *node {
+	tree := newNode()
+	for _, pattern := range patterns {
+		tree.insert(pattern, http.MethodGet, []func(c *Context){noop})
+	}
+	return tree
+}
+
+func TestTreeFind(t *testing.T) {
+	tree := newTestTree(
+		"/",
+		"/users/new",
+		"/users/{id:int}",
+		"/users/:name",
+		"/users/:name/posts",
+		"/a/:x/c",
+		"/a/b/d",
+		"/files/readme",
+		"/files/*path",
+		"/esc/:v",
+	)
+	tests := []struct {
+		path    string
+		pattern string
+		params  map[string]string
+	}{
+		{"/", "/", nil},
+		{"/users/new", "/users/new", nil},
+		{"/users/42", "/users/{id:int}", map[string]string{"id": "42"}},
+		{"/users/kim", "/users/:name", map[string]string{"name": "kim"}},
+		{"/users/new/posts", "/users/:name/posts", map[string]string{"name": "new"}},
+		{"/a/b/d", "/a/b/d", nil},
+		{"/a/b/c", "/a/:x/c", map[string]string{"x": "b"}},
+		{"/files/readme", "/files/readme", nil},
+		{"/files/css/app.css", "/files/*path", map[string]string{"path": "css/app.css"}},
+		{"/files/", "/files/*path", map[string]string{"path": ""}},
+		{"/esc/a%2Fb", "/esc/:v", map[string]string{"v": "a/b"}},
+		{"/esc/%E3%81%82", "/esc/:v", map[string]string{"v": "あ"}},
+		{"/users", "", nil},
+		{"/users/", "", nil},
+		{"/a/b", "", nil},
+		{"/esc/%zz", "", nil},
+	}
+	for _, tt := range tests {
+		n, params := tree.find(tt.path, false)
+		if tt.pattern == "" {
+			if n != nil {
+				t.Errorf("find(%q) = %q, want no match", tt.path, n.pattern)
+			}
+			continue
+		}
+		if n == nil {
+			t.Errorf("find(%q) = no match, want %q", tt.path, tt.pattern)
+			continue
+		}
+		if n.pattern != tt.pattern {
+			t.Errorf("find(%q) = %q, want %q", tt.path, n.pattern, tt.pattern)
+		}
+		var got map[string]string
+		for _, p := range params {
+			if got == nil {
+				got = make(map[string]string)
+			}
+			got[p.key] = p.value
+		}
+		if !reflect.DeepEqual(got, tt.params) {
+			t.Errorf("find(%q) params = %v, want %v", tt.path, got, tt.params)
+		}
+	}
+}
+
+func TestTreeFindFoldCase(t *testing.T) {
+	tree := newTestTree("/ABC", "/abc", "/Abc/x", "/aBc/y", "/users/:id/Posts")
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"/abc", "/abc"},
+		{"/ABC", "/ABC"},
+		{"/aBC", "/ABC"},
+		{"/abc/x", "/Abc/x"},
+		{"/ABC/Y", "/aBc/y"},
+		{"/USERS/1/posts", "/users/:id/Posts"},
+	}
+	for _, tt := range tests {
+		// registration order decides match, not map order
+		for i := 0; i < 20; i++ {
+			n, _ := tree.find(tt.path, true)
+			if n == nil || n.pattern != tt.pattern {
+				t.Fatalf("find(%q) with folding case = %v, want %q", tt.path, n, tt.pattern)
+			}
+		}
+	}
+	if n, _ := tree.find("/abc/x", false); n != nil {
+		t.Errorf("find(/abc/x) = %q, want no match", n.pattern)
+	}
+}
+
+func TestTreeFindStaticNoAlloc(t *testing.T) {
+	tree := newTestTree("/api/v1/users", "/api/v1/users/:id")
+	allocs := testing.AllocsPerRun(100, func() {
+		tree.find("/api/v1/users", false)
+	})
+	if allocs != 0 {
+		t.Errorf("find of static path allocates %v times, want 0", allocs)
+	}
+}
+
+func TestTreeInsertConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+	}{
+		{"duplicate route", []string{"/users", "/users"}},
+		{"parameter name", []string{"/users/:id", "/users/:name"}},
+		{"constrained parameter name", []string{"/users/{id:int}", "/users/{no:int}"}},
+		{"wildcard name", []string{"/files/*path", "/files/*name"}},
+		{"wildcard not last", []string{"/files/*path/x"}},
+		{"empty parameter name", []string{"/users/:"}},
+		{"empty wildcard name", []string{"/files/*"}},
+		{"invalid constraint", []string{"/users/{id:[}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("insert %v does not panic", tt.patterns)
+				}
+			}()
+			newTestTree(tt.patterns...)
+		})
+	}
+}
+
+func TestTreeInsertNoConflict(t *testing.T) {
+	// same parameter with different constraints & different methods are allowed
+	tree := newTestTree("/users/{id:int}", "/users/:name", "/users/{slug:slug}/x")
+	tree.insert("/users/:name", http.MethodPost, []func(c *Context){noop})
+	n, _ := tree.find("/users/kim", false)
+	if n == nil || len(n.handlers) != 2 {
+		t.Fatalf("find(/users/kim) does not have GET & POST handlers")
+	}
+}
+
+// route table of benchmark: 50 resources with list, item & nested routes
+func benchmarkRoutes() []string {
+	routes := make([]string, 0, 300)
+	for i := 0; i < 50; i++ {
+		base := fmt.Sprintf("/api/v1/resource%d", i)
+		routes = append(routes,
+			base,
+			base+"/search",
+			base+"/:id",
+			base+"/:id/items",
+			base+"/:id/items/:item",
+			base+"/:id/files/*path",
+		)
+	}
+	return routes
+}
+
+// ServeMux dispatch replaced by routing tree
+// Static paths are registered to ServeMux, patterns are matched one by one (longer first).
+func newServeMuxRouter(routes []string) *http.ServeMux {
+	mux := http.NewServeMux()
+	var patterns []string
+	for _, p := range routes {
+		if strings.ContainsAny(p, ":*") {
+			patterns = append(patterns, p)
+			continue
+		}
+		mux.HandleFunc(p, func(w http.ResponseWriter, req *http.Request) {})
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		for _, p := range patterns {
+			if _, ok := matchPattern(p, req.URL.EscapedPath()); ok {
+				return
+			}
+		}
+		http.NotFound(w, req)
+	})
+	return mux
+}
+
+// match escaped path to pattern segment by segment
+func matchPattern(pattern, p string) (map[string]string, bool) {
+	patternSegs := strings.Split(pattern, "/")
+	pathSegs := strings.Split(p, "/")
+	params := make(map[string]string)
+	for i, seg := range patternSegs {
+		if strings.HasPrefix(seg, "*") {
+			value, err := url.PathUnescape(strings.Join(pathSegs[min(i, len(pathSegs)):], "/"))
+			if err != nil {
+				return nil, false
+			}
+			params[seg[1:]] = value
+			return params, true
+		}
+		if i >= len(pathSegs) {
+			return nil, false
+		}
+		value, err := url.PathUnescape(pathSegs[i])
+		if err != nil {
+			return nil, false
+		}
+		if strings.HasPrefix(seg, ":") {
+			if value == "" {
+				return nil, false
+			}
+			params[seg[1:]] = value
+		} else if seg != value {
+			return nil, false
+		}
+	}
+	return params, len(patternSegs) == len(pathSegs)
+}
+
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(status int)      {}
+
+func BenchmarkRouter(b *testing.B) {
+	paths := []string{
+		"/api/v1/resource0",
+		"/api/v1/resource49/search",
+		"/api/v1/resource25/42",
+		"/api/v1/resource37/42/items/7",
+		"/api/v1/resource12/42/files/css/app.css",
+	}
+	routes := benchmarkRoutes()
+
+	gorn := NewRouter()
+	for _, p := range routes {
+		gorn.Get(p, noop)
+	}
+	handlers := []struct {
+		name    string
+		handler http.Handler
+	}{
+		{"gorn", gorn},
+		{"ServeMux", newServeMuxRouter(routes)},
+	}
+	for _, h := range handlers {
+		b.Run(h.name, func(b *testing.B) {
+			reqs := make([]*http.Request, len(paths))
+			for i, p := range paths {
+				reqs[i] = httptest.NewRequest(http.MethodGet, p, nil)
+			}
+			w := &discardWriter{header: make(http.Header)}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				clear(w.header)
+				h.handler.ServeHTTP(w, reqs[i%len(reqs)])
+			}
+		})
+	}
+}
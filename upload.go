@@ -0,0 +1,330 @@
+package gorn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// max memory of multipart form (Rest of files are stored on disk)
+const defaultMultipartMemory = 32 << 20
+
+// size of content sniffed to detect content type of file
+const sniffLen = 512
+
+// upload limits of router options (nil options mean no limit)
+func (c *Context) uploadLimits() *RouterOptions {
+	if c.router == nil || c.router.options == nil {
+		return &RouterOptions{}
+	}
+	return c.router.options
+}
+
+// Get Multipart Form Parsed with Upload Limits of Router
+// If Limit is Exceeded, Send Request Entity Too Large (413) or Unsupported Media Type (415) & Return Error
+func (c *Context) MultipartForm() (*multipart.Form, error) {
+	form, err := c.parseMultipartForm()
+	if err != nil {
+		return nil, c.requestError(err)
+	}
+	return form, nil
+}
+
+// Get First File of Multipart Form Field
+// If File is Missing, Send Bad Request (400) & Return Error
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	files := form.File[name]
+	if len(files) == 0 {
+		errs := FieldErrors{{Field: name, Source: "form", Message: "is required"}}
+		return nil, c.requestError(NewHTTPError(http.StatusBadRequest, "bad request").WithDetails(errs).WithErr(errs))
+	}
+	return files[0], nil
+}
+
+// Save Uploaded File to Destination (Parent Directories are Created)
+func (c *Context) SaveUploadedFile(fh *multipart.FileHeader, dst string) error {
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// parse multipart form checking upload limits while parts are read
+// Rejected file is not buffered in memory or on disk, and result is reused by later calls
+func (c *Context) parseMultipartForm() (*multipart.Form, *HTTPError) {
+	if c.multipartErr != nil {
+		return nil, c.multipartErr
+	}
+	if c.request.MultipartForm != nil {
+		return c.request.MultipartForm, nil
+	}
+	form, err := c.readMultipartForm()
+	if err != nil {
+		c.multipartErr = err
+		return nil, err
+	}
+	// populate form values like http.Request.ParseMultipartForm
+	if c.request.Form == nil {
+		c.request.ParseForm()
+	}
+	if c.request.PostForm == nil {
+		c.request.PostForm = make(url.Values)
+	}
+	for k, v := range form.Value {
+		c.request.Form[k] = append(c.request.Form[k], v...)
+		c.request.PostForm[k] = append(c.request.PostForm[k], v...)
+	}
+	c.request.MultipartForm = form
+	return form, nil
+}
+
+// read multipart form of body
+// Parts are checked by multipart reader & re-encoded to form reader through pipe
+func (c *Context) readMultipartForm() (*multipart.Form, *HTTPError) {
+	reader, err := c.multipartReader()
+	if err != nil {
+		return nil, err
+	}
+	memory := c.uploadLimits().MultipartMemory
+	if memory <= 0 {
+		memory = defaultMultipartMemory
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	done := make(chan *HTTPError, 1)
+	go func() {
+		err := copyParts(reader, writer)
+		if err == nil {
+			pw.CloseWithError(writer.Close())
+		} else {
+			pw.CloseWithError(err)
+		}
+		done <- err
+	}()
+	form, formErr := multipart.NewReader(pr, writer.Boundary()).ReadForm(memory)
+	// unblock copying parts if form reader stopped early
+	pr.Close()
+	if err := <-done; err != nil {
+		if form != nil {
+			form.RemoveAll()
+		}
+		return nil, err
+	}
+	if formErr != nil {
+		return nil, uploadError(formErr)
+	}
+	return form, nil
+}
+
+// copy parts checked by multipart reader to multipart writer
+// If form reader is closed, return nil (error of form reader is used)
+func copyParts(reader *MultipartReader, writer *multipart.Writer) *HTTPError {
+	for {
+		part, err := reader.nextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err.(*HTTPError)
+		}
+		dst, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return nil
+		}
+		if _, err := io.Copy(dst, part); err != nil {
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) {
+				return httpErr
+			}
+			if errors.Is(err, io.ErrClosedPipe) {
+				return nil
+			}
+			return uploadError(err)
+		}
+	}
+}
+
+// check content type matches one of allowed types (e.g. "image/png", "image/*")
+func allowsFileType(allowed []string, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range allowed {
+		t = strings.ToLower(t)
+		if t == "*/*" || t == mediaType {
+			return true
+		}
+		if strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// http error of parsing multipart form
+func uploadError(err error) *HTTPError {
+	if httpErr := tooLargeError(err); httpErr != nil {
+		return httpErr
+	}
+	if errors.Is(err, multipart.ErrMessageTooLarge) {
+		return NewHTTPError(http.StatusRequestEntityTooLarge, "request entity too large").WithErr(err)
+	}
+	return NewHTTPError(http.StatusBadRequest, "bad request").WithErr(err)
+}
+
+// http error of file exceeding size limit
+func fileSizeError(name, filename string, limit int64) *HTTPError {
+	errs := FieldErrors{{Field: name, Source: "form", Value: filename, Message: fmt.Sprintf("file exceeds %d bytes", limit)}}
+	return NewHTTPError(http.StatusRequestEntityTooLarge, "request entity too large").WithDetails(errs).WithErr(errs)
+}
+
+// http error of file with content type not allowed
+func fileTypeError(name, filename, contentType string) *HTTPError {
+	errs := FieldErrors{{Field: name, Source: "form", Value: filename, Message: fmt.Sprintf("file type '%s' is not allowed", contentType)}}
+	return NewHTTPError(http.StatusUnsupportedMediaType, "unsupported media type").WithDetails(errs).WithErr(errs)
+}
+
+// Streaming Multipart Reader Checking Upload Limits of Router
+// Parts are Read Without Buffering Whole Form in Memory or on Disk
+type MultipartReader struct {
+	c      *Context
+	reader *multipart.Reader
+	files  int
+}
+
+// Part of Multipart Body
+// Reading File Part Beyond File Size Limit Returns HTTPError (413)
+type UploadPart struct {
+	*multipart.Part
+
+	// Content Type Sniffed From Content (Only File Part)
+	ContentType string
+
+	reader io.Reader
+}
+
+func (p *UploadPart) Read(b []byte) (int, error) {
+	return p.reader.Read(b)
+}
+
+// Get Streaming Multipart Reader
+// If Body is Not Multipart, Send Bad Request (400) & Return Error
+func (c *Context) MultipartReader() (*MultipartReader, error) {
+	reader, err := c.multipartReader()
+	if err != nil {
+		return nil, c.requestError(err)
+	}
+	return reader, nil
+}
+
+// get multipart reader of body limited to max upload size
+func (c *Context) multipartReader() (*MultipartReader, *HTTPError) {
+	if c.request.MultipartForm != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, "bad request").WithErr(errors.New("multipart form is already parsed"))
+	}
+	mediaType, params, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil, NewHTTPError(http.StatusBadRequest, "bad request").WithErr(http.ErrNotMultipart)
+	}
+	if limit := c.uploadLimits().MaxUploadSize; limit > 0 {
+		c.request.Body = http.MaxBytesReader(c.responseWriter.ResponseWriter, c.request.Body, limit)
+	}
+	return &MultipartReader{c: c, reader: multipart.NewReader(c.request.Body, params["boundary"])}, nil
+}
+
+// Get Next Part of Multipart Body
+// At End of Body, Return io.EOF. If Limit is Exceeded, Send Error Status & Return Error
+func (r *MultipartReader) NextPart() (*UploadPart, error) {
+	part, err := r.nextPart()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, r.c.requestError(err.(*HTTPError))
+	}
+	return part, nil
+}
+
+// get next part checking upload limits
+// Return io.EOF at end of body, otherwise error is *HTTPError
+func (r *MultipartReader) nextPart() (*UploadPart, error) {
+	part, err := r.reader.NextPart()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	if part.FileName() == "" {
+		return &UploadPart{Part: part, reader: part}, nil
+	}
+
+	limits := r.c.uploadLimits()
+	r.files++
+	if limits.MaxFiles > 0 && r.files > limits.MaxFiles {
+		return nil, NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("too many files (max %d)", limits.MaxFiles))
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, uploadError(err)
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	if len(limits.AllowedFileTypes) > 0 && !allowsFileType(limits.AllowedFileTypes, contentType) {
+		return nil, fileTypeError(part.FormName(), part.FileName(), contentType)
+	}
+	var reader io.Reader = io.MultiReader(bytes.NewReader(head), part)
+	if limits.MaxFileSize > 0 {
+		reader = &fileSizeReader{part: part, reader: reader, remain: limits.MaxFileSize, limit: limits.MaxFileSize}
+	}
+	return &UploadPart{Part: part, ContentType: contentType, reader: reader}, nil
+}
+
+// reader failing when file exceeds size limit
+type fileSizeReader struct {
+	part   *multipart.Part
+	reader io.Reader
+	remain int64
+	limit  int64
+}
+
+func (r *fileSizeReader) Read(b []byte) (int, error) {
+	if int64(len(b)) > r.remain+1 {
+		b = b[:r.remain+1]
+	}
+	n, err := r.reader.Read(b)
+	if int64(n) > r.remain {
+		r.remain = 0
+		return 0, fileSizeError(r.part.FormName(), r.part.FileName(), r.limit)
+	}
+	r.remain -= int64(n)
+	return n, err
+}
@@ -0,0 +1,157 @@
+package gorn
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// part of multipart test body (file part if filename is set)
+type uploadTestPart struct {
+	name     string
+	filename string
+	content  string
+}
+
+var pngContent = "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 32)
+
+// generate multipart request with parts
+func newMultipartRequest(target string, parts ...uploadTestPart) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, p := range parts {
+		var w io.Writer
+		if p.filename == "" {
+			w, _ = writer.CreateFormField(p.name)
+		} else {
+			w, _ = writer.CreateFormFile(p.name, p.filename)
+		}
+		io.WriteString(w, p.content)
+	}
+	writer.Close()
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadLimits(t *testing.T) {
+	options := &RouterOptions{MaxFiles: 2, MaxFileSize: 64, AllowedFileTypes: []string{"image/*"}}
+	png := uploadTestPart{"file", "a.png", pngContent}
+	tests := []struct {
+		name    string
+		options *RouterOptions
+		parts   []uploadTestPart
+		status  int
+		message string
+	}{
+		{"allowed file", options, []uploadTestPart{{"title", "", "hello"}, png}, http.StatusOK, "hello 40"},
+		{"too many files", options, []uploadTestPart{png, png, png}, http.StatusRequestEntityTooLarge, "too many files (max 2)"},
+		{"too large file", options, []uploadTestPart{{"file", "a.png", pngContent + strings.Repeat("\x00", 64)}}, http.StatusRequestEntityTooLarge, "file: file exceeds 64 bytes"},
+		{"sniffed type is not allowed", options, []uploadTestPart{{"file", "a.png", "plain text"}}, http.StatusUnsupportedMediaType, "file type 'text/plain; charset=utf-8' is not allowed"},
+		{"too large upload", &RouterOptions{MaxUploadSize: 64}, []uploadTestPart{{"file", "a.txt", strings.Repeat("a", 128)}}, http.StatusRequestEntityTooLarge, "request body exceeds 64 bytes"},
+		{"missing file", options, []uploadTestPart{{"title", "", "hello"}}, http.StatusBadRequest, "file: is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.SetOptions(tt.options)
+			r.Post("/", func(c *Context) {
+				fh, err := c.FormFile("file")
+				if err != nil {
+					return
+				}
+				c.SendPlainText(http.StatusOK, fmt.Sprintf("%s %d", c.GetRequest().PostFormValue("title"), fh.Size))
+			})
+			w := serveTestRequest(r, newMultipartRequest("/", tt.parts...))
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("got %d %q, want %d containing %q", w.Code, w.Body.String(), tt.status, tt.message)
+			}
+		})
+	}
+}
+
+func TestUploadParsedOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		parts  []uploadTestPart
+		status int
+	}{
+		{"valid form", []uploadTestPart{{"file", "a.png", pngContent}}, http.StatusOK},
+		{"rejected form", []uploadTestPart{{"file", "a.txt", "plain text"}}, http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			r.SetOptions(&RouterOptions{AllowedFileTypes: []string{"image/png"}})
+			var first, second *multipart.Form
+			var errs [2]error
+			r.Post("/", WithError(func(c *Context) error {
+				first, errs[0] = c.MultipartForm()
+				second, errs[1] = c.MultipartForm()
+				if errs[1] != nil {
+					return errs[1]
+				}
+				c.SendSuccess()
+				return nil
+			}))
+			w := serveTestRequest(r, newMultipartRequest("/", tt.parts...))
+			if w.Code != tt.status {
+				t.Errorf("got %d, want %d", w.Code, tt.status)
+			}
+			if first != second || errs[0] != errs[1] {
+				t.Errorf("second parse = %p %v, want cached %p %v", second, errs[1], first, errs[0])
+			}
+		})
+	}
+}
+
+func TestMultipartReader(t *testing.T) {
+	r := NewRouter()
+	r.SetOptions(&RouterOptions{MaxFileSize: 64, AllowedFileTypes: []string{"image/png"}})
+	r.Post("/", func(c *Context) {
+		reader, err := c.MultipartReader()
+		if err != nil {
+			return
+		}
+		var got []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return
+			}
+			if _, err := io.Copy(io.Discard, part); err != nil {
+				c.SendHTTPError(err.(*HTTPError))
+				return
+			}
+			got = append(got, part.FormName()+"="+part.ContentType)
+		}
+		c.SendPlainText(http.StatusOK, strings.Join(got, ","))
+	})
+	tests := []struct {
+		name    string
+		req     *http.Request
+		status  int
+		message string
+	}{
+		{"sniffed type", newMultipartRequest("/", uploadTestPart{"title", "", "hello"}, uploadTestPart{"file", "a.bin", pngContent}), http.StatusOK, "title=,file=image/png"},
+		{"type not allowed", newMultipartRequest("/", uploadTestPart{"file", "a.png", "<html>"}), http.StatusUnsupportedMediaType, "file type 'text/html; charset=utf-8' is not allowed"},
+		{"too large file", newMultipartRequest("/", uploadTestPart{"file", "a.png", pngContent + strings.Repeat("\x00", 64)}), http.StatusRequestEntityTooLarge, "file: file exceeds 64 bytes"},
+		{"not multipart", newJsonRequest(http.MethodPost, "/", "{}"), http.StatusBadRequest, "bad request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveTestRequest(r, tt.req)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("got %d %q, want %d containing %q", w.Code, w.Body.String(), tt.status, tt.message)
+			}
+		})
+	}
+}
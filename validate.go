@@ -0,0 +1,518 @@
+package gorn
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Field Validated by Validator
+type ValidationField struct {
+	// Name of Field in Errors (e.g. "email" or "items[0].name")
+	Name string
+
+	// Value of Field (Pointer is Dereferenced)
+	Value reflect.Value
+
+	// Parameter of Rule (e.g. "20" of "max=20")
+	Param string
+
+	// Struct Containing Field (For Cross-Field Rules)
+	Parent reflect.Value
+}
+
+// rule of validate tag
+type validationRule struct {
+	name  string
+	param string
+
+	// validator of rule (nil for "required" & "omitempty")
+	fn func(f *ValidationField) bool
+}
+
+// validated field of struct with rules of validate tag
+type fieldValidation struct {
+	index int
+	name  string
+	rules []validationRule
+}
+
+// fields of struct type with checked rules (or error of invalid validate tag)
+type structValidation struct {
+	fields []*fieldValidation
+	err    error
+}
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[string]func(f *ValidationField) bool)
+
+	// validations by struct type (reflect.Type -> *structValidation)
+	validationCache sync.Map
+)
+
+func init() {
+	RegisterValidator("min", func(f *ValidationField) bool {
+		return compareSize(f.Value, f.Param, func(n, p float64) bool { return n >= p })
+	})
+	RegisterValidator("max", func(f *ValidationField) bool {
+		return compareSize(f.Value, f.Param, func(n, p float64) bool { return n <= p })
+	})
+	RegisterValidator("len", func(f *ValidationField) bool {
+		return compareSize(f.Value, f.Param, func(n, p float64) bool { return n == p })
+	})
+	RegisterValidator("email", func(f *ValidationField) bool {
+		addr, err := mail.ParseAddress(f.Value.String())
+		return f.Value.Kind() == reflect.String && err == nil && addr.Address == f.Value.String()
+	})
+	RegisterValidator("url", func(f *ValidationField) bool {
+		u, err := url.Parse(f.Value.String())
+		return f.Value.Kind() == reflect.String && err == nil && u.Scheme != "" && u.Host != ""
+	})
+	RegisterValidator("uuid", func(f *ValidationField) bool {
+		segType, _ := lookupSegmentType("uuid")
+		return f.Value.Kind() == reflect.String && segType.regexp.MatchString(f.Value.String())
+	})
+	RegisterValidator("oneof", func(f *ValidationField) bool {
+		value := fmt.Sprint(f.Value.Interface())
+		for _, option := range strings.Fields(f.Param) {
+			if value == option {
+				return true
+			}
+		}
+		return false
+	})
+	RegisterValidator("eqfield", func(f *ValidationField) bool {
+		other, ok := otherField(f)
+		return ok && reflect.DeepEqual(f.Value.Interface(), other.Interface())
+	})
+	RegisterValidator("nefield", func(f *ValidationField) bool {
+		other, ok := otherField(f)
+		return ok && !reflect.DeepEqual(f.Value.Interface(), other.Interface())
+	})
+	RegisterValidator("gtfield", func(f *ValidationField) bool {
+		other, ok := otherField(f)
+		if !ok {
+			return false
+		}
+		cmp, ok := compareValues(f.Value, other)
+		return ok && cmp > 0
+	})
+	RegisterValidator("gtefield", func(f *ValidationField) bool {
+		other, ok := otherField(f)
+		if !ok {
+			return false
+		}
+		cmp, ok := compareValues(f.Value, other)
+		return ok && cmp >= 0
+	})
+	RegisterValidator("ltfield", func(f *ValidationField) bool {
+		other, ok := otherField(f)
+		if !ok {
+			return false
+		}
+		cmp, ok := compareValues(f.Value, other)
+		return ok && cmp < 0
+	})
+	RegisterValidator("ltefield", func(f *ValidationField) bool {
+		other, ok := otherField(f)
+		if !ok {
+			return false
+		}
+		cmp, ok := compareValues(f.Value, other)
+		return ok && cmp <= 0
+	})
+}
+
+// Regist Validator Used as Rule of Validate Tag (`validate:"name=param"`)
+// Validator Returns Whether Field is Valid. Nil Pointer Fields are Not Validated
+func RegisterValidator(name string, fn func(f *ValidationField) bool) {
+	if name == "required" || name == "omitempty" {
+		panic(fmt.Sprintf("gorn: validator '%s' is reserved", name))
+	}
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[name] = fn
+	// rules are checked again with new validator
+	validationCache.Clear()
+}
+
+// get validator of rule
+func lookupValidator(name string) (func(f *ValidationField) bool, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := validators[name]
+	return fn, ok
+}
+
+// Validate Fields of Struct (or Structs of Slice) by Validate Tags
+// Return FieldErrors of Every Failed Rule (nil if Struct is Valid).
+// If Validate Tag is Invalid (e.g. Unknown Rule), Return Error Other Than FieldErrors
+func Validate(obj interface{}) error {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() {
+		return nil
+	}
+	// struct, slice or array of structs (e.g. batch body)
+	errs, err := validateNested(v, "")
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate Fields of Struct by Validate Tags
+// If Validation is Failed, Send Unprocessable Entity (422) & Return Error with FieldErrors
+// If Validate Tag is Invalid, Send Internal Server Error (500) & Return Error
+func (c *Context) Validate(obj interface{}) error {
+	err := Validate(obj)
+	if err == nil {
+		return nil
+	}
+	errs, ok := err.(FieldErrors)
+	if !ok {
+		log.Printf("gorn: error in %s %s: %v", c.request.Method, c.request.URL.Path, err)
+		return c.requestError(NewHTTPError(http.StatusInternalServerError, "internal server error").WithErr(err))
+	}
+	return c.requestError(NewHTTPError(http.StatusUnprocessableEntity, "unprocessable entity").WithDetails(errs).WithErr(errs))
+}
+
+// validate fields of struct
+func validateStruct(v reflect.Value, prefix string) (FieldErrors, error) {
+	validation := structValidationOf(v.Type())
+	if validation.err != nil {
+		return nil, validation.err
+	}
+	var errs FieldErrors
+	for _, field := range validation.fields {
+		fv := v.Field(field.index)
+		if !fv.CanInterface() {
+			continue
+		}
+		name := prefix + field.name
+		if field.name == "" {
+			name = strings.TrimSuffix(prefix, ".")
+		}
+		if len(field.rules) > 0 {
+			errs = append(errs, validateField(v, fv, name, field.rules)...)
+		}
+		nestedErrs, err := validateNested(fv, name)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, nestedErrs...)
+	}
+	return errs, nil
+}
+
+// get validation of struct type
+// Validate tags are parsed & checked once per type
+func structValidationOf(t reflect.Type) *structValidation {
+	if cached, ok := validationCache.Load(t); ok {
+		return cached.(*structValidation)
+	}
+	validation := &structValidation{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+		rules, err := parseRules(tag, t, field)
+		if err != nil {
+			validation = &structValidation{err: err}
+			break
+		}
+		name := validationName(field)
+		// embedded struct without name is flattened
+		if field.Anonymous && name == field.Name {
+			name = ""
+		}
+		validation.fields = append(validation.fields, &fieldValidation{i, name, rules})
+	}
+	cached, _ := validationCache.LoadOrStore(t, validation)
+	return cached.(*structValidation)
+}
+
+// validate nested structs of field (struct, pointer to struct, slice of structs)
+func validateNested(v reflect.Value, name string) (FieldErrors, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	prefix := name + "."
+	if name == "" {
+		prefix = ""
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return nil, nil
+		}
+		return validateStruct(v, prefix)
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array:
+		default:
+			return nil, nil
+		}
+		var errs FieldErrors
+		for i := 0; i < v.Len(); i++ {
+			nestedErrs, err := validateNested(v.Index(i), fmt.Sprintf("%s[%d]", name, i))
+			if err != nil {
+				return nil, err
+			}
+			errs = append(errs, nestedErrs...)
+		}
+		return errs, nil
+	}
+	return nil, nil
+}
+
+// validate field by rules
+// If field is empty, "omitempty" skips remaining rules & "required" fails
+func validateField(parent, v reflect.Value, name string, rules []validationRule) FieldErrors {
+	var errs FieldErrors
+	empty := isEmptyValue(v)
+	for _, rule := range rules {
+		switch rule.name {
+		case "omitempty":
+			if empty {
+				return errs
+			}
+			continue
+		case "required":
+			if empty {
+				return append(errs, validationError(name, rule, v))
+			}
+			continue
+		}
+		value := v
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return errs
+			}
+			value = value.Elem()
+		}
+		if !rule.fn(&ValidationField{Name: name, Value: value, Param: rule.param, Parent: parent}) {
+			errs = append(errs, validationError(name, rule, value))
+		}
+	}
+	return errs
+}
+
+// parse rules of validate tag of field & check them
+// e.g. "required,min=1,oneof=a b" -> required, min(1), oneof(a b)
+// If rule is unknown or param of built-in rule is invalid, return error
+func parseRules(tag string, parent reflect.Type, field reflect.StructField) ([]validationRule, error) {
+	parts := strings.Split(tag, ",")
+	rules := make([]validationRule, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		rule := validationRule{name: part}
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			rule.name, rule.param = part[:i], part[i+1:]
+		}
+		if err := checkRule(&rule, parent, field.Type); err != nil {
+			return nil, fmt.Errorf("gorn: invalid validate tag of field '%s' of %s: %v", field.Name, parent, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// check rule is known & param is valid, then set validator of rule
+func checkRule(rule *validationRule, parent, t reflect.Type) error {
+	if rule.name == "required" || rule.name == "omitempty" {
+		return nil
+	}
+	fn, ok := lookupValidator(rule.name)
+	if !ok {
+		return fmt.Errorf("unknown rule '%s'", rule.name)
+	}
+	rule.fn = fn
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch rule.name {
+	case "min", "max", "len":
+		if t == durationType {
+			if _, err := time.ParseDuration(rule.param); err != nil {
+				return fmt.Errorf("param of '%s' must be duration, got '%s'", rule.name, rule.param)
+			}
+		} else if _, err := strconv.ParseFloat(rule.param, 64); err != nil {
+			return fmt.Errorf("param of '%s' must be number, got '%s'", rule.name, rule.param)
+		}
+	case "eqfield", "nefield", "gtfield", "gtefield", "ltfield", "ltefield":
+		if _, ok := parent.FieldByName(rule.param); !ok {
+			return fmt.Errorf("field '%s' of '%s' is not found", rule.param, rule.name)
+		}
+	}
+	return nil
+}
+
+// get name of field in errors
+// Name of json, form, query, header or path tag is used, if not exists, field name is used
+func validationName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query", "header", "path"} {
+		name := strings.Split(field.Tag.Get(key), ",")[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// generate field error of failed rule
+func validationError(name string, rule validationRule, v reflect.Value) *FieldError {
+	return &FieldError{
+		Field:   name,
+		Rule:    rule.name,
+		Param:   rule.param,
+		Message: validationMessage(rule, v),
+	}
+}
+
+// message of failed rule
+func validationMessage(rule validationRule, v reflect.Value) string {
+	size := ""
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		size = "length "
+	}
+	switch rule.name {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("%smust be at least %s", size, rule.param)
+	case "max":
+		return fmt.Sprintf("%smust be at most %s", size, rule.param)
+	case "len":
+		return fmt.Sprintf("%smust be %s", size, rule.param)
+	case "email":
+		return "must be email address"
+	case "url":
+		return "must be URL"
+	case "uuid":
+		return "must be UUID"
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", rule.param)
+	case "eqfield":
+		return fmt.Sprintf("must be equal to %s", rule.param)
+	case "nefield":
+		return fmt.Sprintf("must not be equal to %s", rule.param)
+	case "gtfield":
+		return fmt.Sprintf("must be greater than %s", rule.param)
+	case "gtefield":
+		return fmt.Sprintf("must be greater than or equal to %s", rule.param)
+	case "ltfield":
+		return fmt.Sprintf("must be less than %s", rule.param)
+	case "ltefield":
+		return fmt.Sprintf("must be less than or equal to %s", rule.param)
+	}
+	return fmt.Sprintf("failed on '%s'", rule.name)
+}
+
+// check value is zero, nil or has no elements
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// compare number or length (string, slice, map) of value to param
+// Param of time.Duration is duration (e.g. "1m"). Invalid param fails
+func compareSize(v reflect.Value, param string, cmp func(n, p float64) bool) bool {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(param)
+		return err == nil && cmp(float64(v.Int()), float64(d))
+	}
+	p, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return cmp(float64(utf8.RuneCountInString(v.String())), p)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return cmp(float64(v.Len()), p)
+	}
+	n, ok := numberValue(v)
+	return ok && cmp(n, p)
+}
+
+// get number of numeric value
+func numberValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// get other field of cross-field rule (Pointer is dereferenced)
+// Return whether field is found (Field promoted through nil embedded pointer is not found)
+func otherField(f *ValidationField) (reflect.Value, bool) {
+	field, ok := f.Parent.Type().FieldByName(f.Param)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	other, err := f.Parent.FieldByIndexErr(field.Index)
+	if err != nil || !other.CanInterface() {
+		return reflect.Value{}, false
+	}
+	for other.Kind() == reflect.Ptr && !other.IsNil() {
+		other = other.Elem()
+	}
+	return other, true
+}
+
+// compare numbers or times
+// Return -1, 0, 1 & whether values are comparable
+func compareValues(a, b reflect.Value) (int, bool) {
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	x, ok := numberValue(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := numberValue(b)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
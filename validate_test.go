@@ -0,0 +1,166 @@
+package gorn
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateTestEmbedded struct {
+	X int
+}
+
+type validateTestCrossField struct {
+	*validateTestEmbedded
+	Y int `validate:"eqfield=X"`
+}
+
+func TestValidateCrossFieldNilEmbedded(t *testing.T) {
+	tests := []struct {
+		name  string
+		obj   *validateTestCrossField
+		valid bool
+	}{
+		{"nil embedded pointer", &validateTestCrossField{Y: 1}, false},
+		{"equal", &validateTestCrossField{&validateTestEmbedded{X: 1}, 1}, true},
+		{"not equal", &validateTestCrossField{&validateTestEmbedded{X: 2}, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.obj)
+			if tt.valid != (err == nil) {
+				t.Fatalf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+			if errs, ok := err.(FieldErrors); err != nil && (!ok || errs[0].Field != "Y" || errs[0].Rule != "eqfield") {
+				t.Errorf("Validate() = %#v, want eqfield error of Y", err)
+			}
+		})
+	}
+}
+
+type validateTestItem struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestValidateTopLevelSlice(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    interface{}
+		fields []string
+	}{
+		{"slice", &[]validateTestItem{{"a"}, {}}, []string{"[1].name"}},
+		{"slice of pointers", []*validateTestItem{{}, nil, {"b"}}, []string{"[0].name"}},
+		{"array", &[2]validateTestItem{}, []string{"[0].name", "[1].name"}},
+		{"valid slice", []validateTestItem{{"a"}}, nil},
+		{"slice of strings", []string{""}, nil},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.obj)
+			var fields []string
+			if err != nil {
+				errs, ok := err.(FieldErrors)
+				if !ok {
+					t.Fatalf("Validate() = %v, want FieldErrors", err)
+				}
+				for _, e := range errs {
+					fields = append(fields, e.Field)
+				}
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fails on %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+type validateTestSignup struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"min=8"`
+	Confirm  string `json:"confirm" validate:"eqfield=Password"`
+	Role     string `json:"role" validate:"omitempty,oneof=admin user"`
+}
+
+type validateTestInvalidTag struct {
+	Name string `json:"name" validate:"min=x"`
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    func() interface{}
+		body   string
+		status int
+		plain  string
+		fields []FieldError
+	}{
+		{
+			"field errors", func() interface{} { return &validateTestSignup{} },
+			`{"email":"a","password":"short","confirm":"other","role":"root"}`, http.StatusUnprocessableEntity,
+			"unprocessable entity\nemail: must be email address (email)\npassword: length must be at least 8 (min=8)\nconfirm: must be equal to Password (eqfield=Password)\nrole: must be one of [admin user] (oneof=admin user)\n",
+			[]FieldError{
+				{Field: "email", Rule: "email", Message: "must be email address"},
+				{Field: "password", Rule: "min", Param: "8", Message: "length must be at least 8"},
+				{Field: "confirm", Rule: "eqfield", Param: "Password", Message: "must be equal to Password"},
+				{Field: "role", Rule: "oneof", Param: "admin user", Message: "must be one of [admin user]"},
+			},
+		},
+		{
+			"valid body", func() interface{} { return &validateTestSignup{} },
+			`{"email":"a@example.com","password":"password","confirm":"password"}`, http.StatusOK, "ok", nil,
+		},
+		{
+			"cross field behind nil embedded pointer", func() interface{} { return &validateTestCrossField{} },
+			`{"Y":1}`, http.StatusUnprocessableEntity, "unprocessable entity\nY: must be equal to X (eqfield=X)\n",
+			[]FieldError{{Field: "Y", Rule: "eqfield", Param: "X", Message: "must be equal to X"}},
+		},
+		{
+			"top-level slice", func() interface{} { return &[]validateTestItem{} },
+			`[{"name":"a"},{}]`, http.StatusUnprocessableEntity, "unprocessable entity\n[1].name: is required (required)\n",
+			[]FieldError{{Field: "[1].name", Rule: "required", Message: "is required"}},
+		},
+		{
+			"invalid tag", func() interface{} { return &validateTestInvalidTag{} },
+			`{"name":"a"}`, http.StatusInternalServerError, "internal server error\n", nil,
+		},
+	}
+	for _, tt := range tests {
+		for _, renderer := range []string{"plain", "json"} {
+			t.Run(tt.name+"/"+renderer, func(t *testing.T) {
+				r := NewRouter()
+				if renderer == "json" {
+					r.SetErrorRenderer(JsonErrorRenderer)
+				}
+				r.Post("/", func(c *Context) {
+					if c.BindJsonBody(tt.obj()) == nil {
+						c.SendPlainText(http.StatusOK, "ok")
+					}
+				})
+				w := serveTestRequest(r, newJsonRequest(http.MethodPost, "/", tt.body))
+				if w.Code != tt.status {
+					t.Fatalf("got %d %q, want %d", w.Code, w.Body.String(), tt.status)
+				}
+				if renderer == "plain" || tt.status == http.StatusOK {
+					if w.Body.String() != tt.plain {
+						t.Errorf("body = %q, want %q", w.Body.String(), tt.plain)
+					}
+					return
+				}
+				var got struct {
+					Status  int          `json:"status"`
+					Message string       `json:"message"`
+					Details []FieldError `json:"details"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != tt.status || got.Message != strings.SplitN(tt.plain, "\n", 2)[0] || !reflect.DeepEqual(got.Details, tt.fields) {
+					t.Errorf("body = %+v, want %d with details %+v", got, tt.status, tt.fields)
+				}
+			})
+		}
+	}
+}